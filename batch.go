@@ -0,0 +1,239 @@
+package pbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const batchEndpoint = "/api/batch"
+
+// Batch collects record operations across collections and submits them to
+// PocketBase's /api/batch endpoint, which applies them in a single transaction.
+// The endpoint must be enabled in the PocketBase settings; its default limit is 50 operations.
+type Batch struct {
+	client   AuthenticatedClient
+	requests []batchRequest
+	err      error
+}
+
+type batchRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   any    `json:"body,omitempty"`
+}
+
+// BatchResult is the response of a single batched operation.
+type BatchResult struct {
+	Status int
+	Body   json.RawMessage
+}
+
+// Decode unmarshals the operation's response body into dst.
+func (r BatchResult) Decode(dst any) error {
+	if len(r.Body) == 0 || string(r.Body) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(r.Body, dst); err != nil {
+		return fmt.Errorf("decode batch result: %w", err)
+	}
+	return nil
+}
+
+// BatchError reports which operation caused a batch transaction to fail.
+// Err carries the mapped error of the failing operation, so errors.Is works with the sentinel errors.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d failed: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatch creates an empty batch bound to client.
+func NewBatch(client AuthenticatedClient) *Batch {
+	return &Batch{client: client}
+}
+
+// Create queues the creation of a record in collection.
+func (b *Batch) Create(collection string, record any) *Batch {
+	return b.add(http.MethodPost, collection, "", record)
+}
+
+// Update queues a patch of the record with id in collection.
+func (b *Batch) Update(collection, id string, record any) *Batch {
+	if strings.TrimSpace(id) == "" {
+		return b.fail(errors.New("id is required"))
+	}
+	return b.add(http.MethodPatch, collection, id, record)
+}
+
+// Upsert queues an upsert: the record is updated when its "id" exists and created otherwise.
+func (b *Batch) Upsert(collection string, record any) *Batch {
+	return b.add(http.MethodPut, collection, "", record)
+}
+
+// Delete queues the deletion of the record with id in collection.
+func (b *Batch) Delete(collection, id string) *Batch {
+	if strings.TrimSpace(id) == "" {
+		return b.fail(errors.New("id is required"))
+	}
+	return b.add(http.MethodDelete, collection, id, nil)
+}
+
+// Len returns the number of queued operations.
+func (b *Batch) Len() int {
+	return len(b.requests)
+}
+
+// Send submits all queued operations atomically and returns one result per operation, in order.
+// If any operation fails, nothing is applied and a *BatchError naming the failing index is returned.
+func (b *Batch) Send(ctx context.Context) ([]BatchResult, error) {
+	if b.client == nil {
+		return nil, errors.New("batch client is nil")
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.requests) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(map[string]any{"requests": b.requests})
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch: %w", err)
+	}
+
+	resp, err := b.client.Do(ctx, http.MethodPost, batchEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if batchErr := parseBatchError(resp.StatusCode, body); batchErr != nil {
+			return nil, batchErr
+		}
+		return nil, mapHTTPError(resp.StatusCode, body)
+	}
+
+	var results []struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	out := make([]BatchResult, len(results))
+	for i, res := range results {
+		out[i] = BatchResult{Status: res.Status, Body: res.Body}
+	}
+	return out, nil
+}
+
+func (b *Batch) add(method, collection, id string, body any) *Batch {
+	collection = strings.TrimSpace(collection)
+	if collection == "" {
+		return b.fail(errors.New("collection is required"))
+	}
+
+	path := fmt.Sprintf("/api/collections/%s/records", url.PathEscape(collection))
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	b.requests = append(b.requests, batchRequest{Method: method, URL: path, Body: body})
+	return b
+}
+
+func (b *Batch) fail(err error) *Batch {
+	if b.err == nil {
+		b.err = fmt.Errorf("batch operation %d: %w", len(b.requests), err)
+	}
+	return b
+}
+
+// BatchCreate queues the creation of record in the repository's collection.
+func (r *Repository[T]) BatchCreate(b *Batch, record T) {
+	b.Create(r.collection, record)
+}
+
+// BatchUpdate queues a patch of the record with id in the repository's collection.
+func (r *Repository[T]) BatchUpdate(b *Batch, id string, record T) {
+	b.Update(r.collection, id, record)
+}
+
+// BatchUpsert queues an upsert of record in the repository's collection.
+func (r *Repository[T]) BatchUpsert(b *Batch, record T) {
+	b.Upsert(r.collection, record)
+}
+
+// BatchDelete queues the deletion of the record with id in the repository's collection.
+func (r *Repository[T]) BatchDelete(b *Batch, id string) {
+	b.Delete(r.collection, id)
+}
+
+// batchFailure is the per-request entry PocketBase reports under data.requests.
+type batchFailure struct {
+	Code     string           `json:"code"`
+	Message  string           `json:"message"`
+	Response *batchFailResult `json:"response"`
+	Params   struct {
+		Response *batchFailResult `json:"response"`
+	} `json:"params"`
+}
+
+type batchFailResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// parseBatchError extracts the failing operation from a failed batch response.
+// It returns nil when the body does not describe a batch transaction failure.
+func parseBatchError(status int, body []byte) *BatchError {
+	var payload struct {
+		Data struct {
+			Requests map[string]batchFailure `json:"requests"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(body, &payload) != nil || len(payload.Data.Requests) == 0 {
+		return nil
+	}
+
+	for key, failure := range payload.Data.Requests {
+		index, convErr := strconv.Atoi(key)
+		if convErr != nil {
+			continue
+		}
+
+		result := failure.Response
+		if result == nil {
+			result = failure.Params.Response
+		}
+		if result == nil || result.Status == 0 {
+			msg := failure.Message
+			if msg == "" {
+				msg = failure.Code
+			}
+			return &BatchError{Index: index, Err: wrapIfMessage(mapHTTPError(status, nil), msg)}
+		}
+		return &BatchError{Index: index, Err: mapHTTPError(result.Status, result.Body)}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchSendCollectsOperations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/batch" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var payload struct {
+			Requests []struct {
+				Method string         `json:"method"`
+				URL    string         `json:"url"`
+				Body   map[string]any `json:"body"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		got := make([]string, 0, len(payload.Requests))
+		for _, req := range payload.Requests {
+			got = append(got, req.Method+" "+req.URL)
+		}
+		want := "POST /api/collections/test/records|PATCH /api/collections/test/records/abc|PUT /api/collections/other/records|DELETE /api/collections/test/records/old"
+		if strings.Join(got, "|") != want {
+			t.Fatalf("unexpected requests: %v", got)
+		}
+		if payload.Requests[0].Body["name"] != "new" {
+			t.Fatalf("unexpected create body: %v", payload.Requests[0].Body)
+		}
+		_, _ = w.Write([]byte(`[
+			{"status":200,"body":{"id":"n1","name":"new"}},
+			{"status":200,"body":{"id":"abc","name":"changed"}},
+			{"status":200,"body":{"id":"u1"}},
+			{"status":204,"body":null}
+		]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	repo := NewRepository[testRecord](client, "test")
+
+	batch := NewBatch(client)
+	repo.BatchCreate(batch, testRecord{Name: "new"})
+	repo.BatchUpdate(batch, "abc", testRecord{Name: "changed"})
+	batch.Upsert("other", map[string]any{"id": "u1"})
+	repo.BatchDelete(batch, "old")
+
+	results, err := batch.Send(context.Background())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(results) != 4 || results[3].Status != http.StatusNoContent {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	var created testRecord
+	if err := results[0].Decode(&created); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if created.ID != "n1" {
+		t.Fatalf("unexpected created record: %+v", created)
+	}
+}
+
+func TestBatchSendReportsFailingIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{
+			"status":400,
+			"message":"Batch transaction failed.",
+			"data":{"requests":{"1":{
+				"code":"batch_request_failed",
+				"message":"Batch request failed.",
+				"response":{"status":400,"body":{"status":400,"message":"Failed to create record.","data":{"name":{"code":"validation_required","message":"Cannot be blank."}}}}
+			}}}
+		}`))
+	}))
+	defer server.Close()
+
+	batch := NewBatch(newTestClient(t, server))
+	batch.Create("test", map[string]any{"name": "ok"})
+	batch.Create("test", map[string]any{})
+
+	_, err := batch.Send(context.Background())
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchError, got %v", err)
+	}
+	if batchErr.Index != 1 {
+		t.Fatalf("expected failing index 1, got %d", batchErr.Index)
+	}
+	if !errors.Is(err, ErrBadRequest) || !strings.Contains(err.Error(), "name: Cannot be blank.") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBatchRejectsMissingID(t *testing.T) {
+	batch := NewBatch(&authenticatedClient{})
+	batch.Create("test", map[string]any{})
+	batch.Delete("test", " ")
+
+	_, err := batch.Send(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "batch operation 1") {
+		t.Fatalf("expected error naming operation 1, got %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultBulkBatchSize   = 50
+	defaultBulkConcurrency = 4
+)
+
+// ErrEmptyFilter is returned by bulk operations given an empty filter, which
+// would match every record, unless BulkOptions.AllowAll is set.
+var ErrEmptyFilter = errors.New("empty filter matches every record")
+
+// BulkOptions configures DeleteWhere and UpdateWhere.
+type BulkOptions struct {
+	// DryRun only counts the matching records without changing them.
+	DryRun bool
+	// BatchSize is the number of operations per batch request; defaults to 50,
+	// PocketBase's default batch limit.
+	BatchSize int
+	// Concurrency bounds parallel single-record requests when the batch API is unavailable; defaults to 4.
+	Concurrency int
+	// AllowAll permits an empty filter, applying the operation to the whole collection.
+	AllowAll bool
+}
+
+// BulkResult reports the outcome of a bulk operation.
+type BulkResult struct {
+	Matched  int
+	Affected int
+	Failed   int
+	// Errors maps the ids of failed records to their errors. It also holds
+	// after-hook errors for records that were changed and counted as Affected.
+	Errors map[string]error
+}
+
+// bulkOp describes one kind of bulk write. prepare runs the before-hooks for a
+// record and returns the body to send; after runs the after-hooks with the
+// record returned by the server, which is nil for deletes.
+type bulkOp[T any] struct {
+	prepare func(ctx context.Context, id string) (any, error)
+	queue   func(b *Batch, id string, body any)
+	send    func(ctx context.Context, id string, body any) (*T, error)
+	after   func(ctx context.Context, id string, record *T) error
+}
+
+// DeleteWhere deletes every record matching filter. Matching ids are collected
+// first, then deleted through the batch API in chunks of opts.BatchSize, split
+// further if the server allows fewer requests per batch. When the batch API is
+// disabled, or a chunk fails, records are deleted one by one with bounded
+// concurrency so that failures are attributed per record. An empty filter is
+// rejected with ErrEmptyFilter unless opts.AllowAll is set.
+// BeforeDelete and AfterDelete hooks run for every record; a record rejected
+// by a before-hook is reported as failed and left in place.
+func (r *Repository[T]) DeleteWhere(ctx context.Context, filter string, opts BulkOptions) (*BulkResult, error) {
+	return r.bulk(ctx, filter, opts, bulkOp[T]{
+		prepare: func(ctx context.Context, id string) (any, error) {
+			return nil, r.hooks.beforeDelete(ctx, id)
+		},
+		queue: func(b *Batch, id string, _ any) { b.Delete(r.collection, id) },
+		send: func(ctx context.Context, id string, _ any) (*T, error) {
+			return nil, r.delete(ctx, id)
+		},
+		after: func(ctx context.Context, id string, _ *T) error {
+			return r.hooks.afterDelete(ctx, id)
+		},
+	})
+}
+
+// UpdateWhere applies patch to every record matching filter, using the same
+// batching and fallback strategy as DeleteWhere. BeforePatch hooks run on a
+// copy of patch per record, and AfterUpdate hooks receive each updated record.
+func (r *Repository[T]) UpdateWhere(ctx context.Context, filter string, patch *FieldPatch, opts BulkOptions) (*BulkResult, error) {
+	if patch == nil || patch.Len() == 0 {
+		return nil, errors.New("patch is empty")
+	}
+	return r.bulk(ctx, filter, opts, bulkOp[T]{
+		prepare: func(ctx context.Context, id string) (any, error) {
+			p := patch.clone()
+			return p, r.hooks.beforePatch(ctx, id, p)
+		},
+		queue: func(b *Batch, id string, body any) { b.Update(r.collection, id, body) },
+		send: func(ctx context.Context, id string, body any) (*T, error) {
+			return r.patch(ctx, id, body)
+		},
+		after: func(ctx context.Context, _ string, record *T) error {
+			if record == nil {
+				// The server returned no body, so there is nothing to pass on.
+				return nil
+			}
+			return r.hooks.afterUpdate(ctx, record)
+		},
+	})
+}
+
+func (r *Repository[T]) bulk(ctx context.Context, filter string, opts BulkOptions, op bulkOp[T]) (*BulkResult, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if strings.TrimSpace(filter) == "" && !opts.AllowAll {
+		return nil, ErrEmptyFilter
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBulkBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBulkConcurrency
+	}
+
+	ids, err := r.matchingIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{Matched: len(ids), Errors: make(map[string]error)}
+	if opts.DryRun || len(ids) == 0 {
+		return result, nil
+	}
+
+	state := &bulkState{useBatch: true, batchSize: opts.BatchSize, concurrency: opts.Concurrency}
+	for start := 0; start < len(ids); start += opts.BatchSize {
+		chunk := make([]string, 0, opts.BatchSize)
+		bodies := make(map[string]any, opts.BatchSize)
+		for _, id := range ids[start:min(start+opts.BatchSize, len(ids))] {
+			body, err := op.prepare(ctx, id)
+			if err != nil {
+				result.Failed++
+				result.Errors[id] = err
+				continue
+			}
+			chunk = append(chunk, id)
+			bodies[id] = body
+		}
+
+		if err := r.bulkSend(ctx, chunk, bodies, state, op, result); err != nil {
+			return result, err
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// bulkState is what a bulk operation learns about the server while it runs.
+type bulkState struct {
+	useBatch    bool
+	batchSize   int
+	concurrency int
+}
+
+// bulkSend writes chunk through the batch API, splitting it to the largest
+// batch the server accepts, and falls back to single requests.
+func (r *Repository[T]) bulkSend(ctx context.Context, chunk []string, bodies map[string]any, state *bulkState, op bulkOp[T], result *BulkResult) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	if state.useBatch && len(chunk) > state.batchSize {
+		for start := 0; start < len(chunk); start += state.batchSize {
+			if err := r.bulkSend(ctx, chunk[start:min(start+state.batchSize, len(chunk))], bodies, state, op, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if state.useBatch {
+		batch := NewBatch(r.client)
+		for _, id := range chunk {
+			op.queue(batch, id, bodies[id])
+		}
+		results, err := batch.Send(ctx)
+		if err == nil {
+			r.bulkCommitted(ctx, chunk, results, op, result)
+			return nil
+		}
+
+		var batchErr *BatchError
+		switch {
+		case errors.As(err, &batchErr):
+			// The chunk was rolled back; retry it record by record to isolate failures.
+		case isBatchTooLarge(err) && len(chunk) > 1:
+			// The server's batch limit is lower than BatchSize; retry in smaller batches.
+			state.batchSize = max(len(chunk)/2, 1)
+			return r.bulkSend(ctx, chunk, bodies, state, op, result)
+		case errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) || isBatchTooLarge(err):
+			// Batch API disabled or unavailable on this server.
+			state.useBatch = false
+		default:
+			return err
+		}
+	}
+
+	r.bulkSingles(ctx, chunk, bodies, state.concurrency, op, result)
+	return nil
+}
+
+// isBatchTooLarge reports whether err rejects a batch for exceeding the
+// server's maximum number of requests per batch.
+func isBatchTooLarge(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr) && validationErr.Fields["requests"].Code == "validation_length_too_long"
+}
+
+// bulkCommitted runs the after-hooks for a chunk that was applied by a batch request.
+func (r *Repository[T]) bulkCommitted(ctx context.Context, ids []string, results []BatchResult, op bulkOp[T], result *BulkResult) {
+	for i, id := range ids {
+		result.Affected++
+
+		var record *T
+		if i < len(results) && len(results[i].Body) > 0 {
+			record = new(T)
+			if err := results[i].Decode(record); err != nil {
+				result.Errors[id] = err
+				continue
+			}
+		}
+		if err := op.after(ctx, id, record); err != nil {
+			result.Errors[id] = err
+		}
+	}
+}
+
+// bulkSingles sends each id with bounded concurrency and records the outcome.
+func (r *Repository[T]) bulkSingles(ctx context.Context, ids []string, bodies map[string]any, concurrency int, op bulkOp[T], result *BulkResult) {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			record, err := op.send(ctx, id, bodies[id])
+			var hookErr error
+			if err == nil {
+				hookErr = op.after(ctx, id, record)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed++
+				result.Errors[id] = err
+				return
+			}
+			result.Affected++
+			if hookErr != nil {
+				result.Errors[id] = hookErr
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// matchingIDs collects the ids of all records matching filter.
+func (r *Repository[T]) matchingIDs(ctx context.Context, filter string) ([]string, error) {
+	repo := NewRepository[struct {
+		ID string `json:"id"`
+	}](r.client, r.collection)
+
+	ids := make([]string, 0)
+	for item, err := range repo.All(ctx, ListOptions{Filter: filter, Sort: "id", Fields: []string{"id"}}) {
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, item.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,184 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bulkTestServer lists ids r1..r5 and records how they were deleted.
+type bulkTestServer struct {
+	mu            sync.Mutex
+	batchAllowed  bool
+	batchRequests int
+	batchSizes    []int
+	maxRequests   int
+	singleDeletes []string
+	failID        string
+}
+
+func (s *bulkTestServer) handle(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch {
+		case r.URL.Path == userAuthEndpoint:
+			// 403 responses clear the token, so the client re-authenticates.
+			_, _ = w.Write([]byte(`{"token":"test-token"}`))
+		case r.Method == http.MethodGet:
+			if r.URL.Query().Get("fields") != "id" {
+				t.Fatalf("expected id projection, got %s", r.URL.RawQuery)
+			}
+			items := []testRecord{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}, {ID: "r5"}}
+			writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 200})
+		case r.URL.Path == "/api/batch":
+			s.batchRequests++
+			if !s.batchAllowed {
+				writeJSON(w, http.StatusForbidden, map[string]any{"message": "Batch requests are not allowed."})
+				return
+			}
+			var payload struct {
+				Requests []batchRequest `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			if s.maxRequests > 0 && len(payload.Requests) > s.maxRequests {
+				writeJSON(w, http.StatusBadRequest, map[string]any{
+					"message": "Invalid batch request data.",
+					"data": map[string]any{"requests": map[string]any{
+						"code": "validation_length_too_long", "message": "The length must be no more than " + strconv.Itoa(s.maxRequests) + ".",
+					}},
+				})
+				return
+			}
+			s.batchSizes = append(s.batchSizes, len(payload.Requests))
+			for i, req := range payload.Requests {
+				if s.failID != "" && strings.HasSuffix(req.URL, "/"+s.failID) {
+					writeJSON(w, http.StatusBadRequest, map[string]any{
+						"data": map[string]any{"requests": map[string]any{
+							strconv.Itoa(i): map[string]any{"code": "batch_request_failed", "message": "Batch request failed."},
+						}},
+					})
+					return
+				}
+			}
+			results := make([]map[string]any, len(payload.Requests))
+			for i := range results {
+				results[i] = map[string]any{"status": 204}
+			}
+			writeJSON(w, http.StatusOK, results)
+		case r.Method == http.MethodDelete:
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			if id == s.failID {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			s.singleDeletes = append(s.singleDeletes, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func TestRepositoryDeleteWhereUsesBatches(t *testing.T) {
+	state := &bulkTestServer{batchAllowed: true}
+	server := httptest.NewServer(state.handle(t))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	res, err := repo.DeleteWhere(context.Background(), Eq("status", "old"), BulkOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("DeleteWhere: %v", err)
+	}
+	if res.Matched != 5 || res.Affected != 5 || res.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if state.batchRequests != 3 || len(state.singleDeletes) != 0 {
+		t.Fatalf("expected 3 batches and no single deletes, got %d/%v", state.batchRequests, state.singleDeletes)
+	}
+}
+
+func TestRepositoryDeleteWhereFallsBackToSingleRequests(t *testing.T) {
+	state := &bulkTestServer{failID: "r3"}
+	server := httptest.NewServer(state.handle(t))
+	defer server.Close()
+
+	client := newTestClient(t, server).(*authenticatedClient)
+	client.creds = Credentials{Email: "admin@example.com", Password: "password"}
+	client.authEndpoint = userAuthEndpoint
+	repo := NewRepository[testRecord](client, "test")
+
+	res, err := repo.DeleteWhere(context.Background(), "", BulkOptions{BatchSize: 2, Concurrency: 2, AllowAll: true})
+	if err != nil {
+		t.Fatalf("DeleteWhere: %v", err)
+	}
+	if res.Affected != 4 || res.Failed != 1 || !errors.Is(res.Errors["r3"], ErrForbidden) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if state.batchRequests != 1 {
+		t.Fatalf("expected batch API to be probed once, got %d", state.batchRequests)
+	}
+}
+
+func TestRepositoryUpdateWhereDryRun(t *testing.T) {
+	state := &bulkTestServer{batchAllowed: true}
+	server := httptest.NewServer(state.handle(t))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	res, err := repo.UpdateWhere(context.Background(), "done=true", Patch().Set("archived", true), BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("UpdateWhere: %v", err)
+	}
+	if res.Matched != 5 || res.Affected != 0 || state.batchRequests != 0 {
+		t.Fatalf("dry run should not write: %+v (batches %d)", res, state.batchRequests)
+	}
+}
+
+func TestRepositoryDeleteWhereRejectsEmptyFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	if _, err := repo.DeleteWhere(context.Background(), " ", BulkOptions{}); !errors.Is(err, ErrEmptyFilter) {
+		t.Fatalf("expected ErrEmptyFilter, got %v", err)
+	}
+	if _, err := repo.UpdateWhere(context.Background(), "", Patch().Set("x", 1), BulkOptions{}); !errors.Is(err, ErrEmptyFilter) {
+		t.Fatalf("expected ErrEmptyFilter, got %v", err)
+	}
+}
+
+func TestRepositoryDeleteWhereShrinksBatchesToServerLimit(t *testing.T) {
+	state := &bulkTestServer{batchAllowed: true, maxRequests: 2}
+	server := httptest.NewServer(state.handle(t))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	res, err := repo.DeleteWhere(context.Background(), "status='old'", BulkOptions{})
+	if err != nil {
+		t.Fatalf("DeleteWhere: %v", err)
+	}
+	if res.Affected != 5 || res.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(state.singleDeletes) != 0 {
+		t.Fatalf("expected no single-record fallback, got %v", state.singleDeletes)
+	}
+	for _, size := range state.batchSizes {
+		if size > 2 {
+			t.Fatalf("batch of %d exceeds the server limit: %v", size, state.batchSizes)
+		}
+	}
+}
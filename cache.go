@@ -0,0 +1,396 @@
+package pbclient
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheTTL          = time.Minute
+	defaultCacheMaxEntries   = 1000
+	defaultCacheFetchTimeout = 30 * time.Second
+)
+
+// CacheOptions configures a CachedRepository.
+type CacheOptions struct {
+	// TTL is how long results stay cached; defaults to one minute.
+	TTL time.Duration
+	// MaxEntries bounds the cache; the least recently used entries are evicted first. Defaults to 1000.
+	MaxEntries int
+	// NegativeTTL is how long ErrNotFound results from Get stay cached. Zero uses TTL,
+	// a negative value disables negative caching.
+	NegativeTTL time.Duration
+	// FetchTimeout bounds a request shared by concurrent misses. The request
+	// outlives the caller that started it, so it does not use that caller's
+	// context. Defaults to 30 seconds.
+	FetchTimeout time.Duration
+}
+
+// CachedRepository is a read-through cache in front of a Repository. Get and List
+// results are cached with a TTL in a size-bounded LRU, and concurrent misses for
+// the same key share one request. Records are cached as raw JSON and decoded for
+// every caller, so callers may modify the records they receive.
+//
+// The write methods of CachedRepository invalidate the cache after the write.
+// Writes made through Repository, or elsewhere, should be reported with
+// Invalidate or InvalidateAll, e.g. from realtime events.
+type CachedRepository[T any] struct {
+	repo *Repository[T]
+	raw  *Repository[json.RawMessage]
+
+	ttl          time.Duration
+	negativeTTL  time.Duration
+	maxEntries   int
+	fetchTimeout time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	flights map[string]*cacheFlight
+	gen     uint64
+}
+
+type cacheEntry struct {
+	key     string
+	id      string // record id for Get entries; empty for List entries
+	value   any
+	err     error
+	expires time.Time
+}
+
+type cacheFlight struct {
+	id    string // as in cacheEntry
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// NewCachedRepository wraps repo with a cache.
+func NewCachedRepository[T any](repo *Repository[T], opts CacheOptions) *CachedRepository[T] {
+	c := &CachedRepository[T]{
+		repo: repo,
+		// Cache raw JSON, so cached records are never shared with callers.
+		raw:          repo.rawRepository(),
+		ttl:          opts.TTL,
+		negativeTTL:  opts.NegativeTTL,
+		maxEntries:   opts.MaxEntries,
+		fetchTimeout: opts.FetchTimeout,
+		now:          time.Now,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+		flights:      make(map[string]*cacheFlight),
+	}
+	if c.ttl <= 0 {
+		c.ttl = defaultCacheTTL
+	}
+	if c.negativeTTL == 0 {
+		c.negativeTTL = c.ttl
+	}
+	if c.maxEntries <= 0 {
+		c.maxEntries = defaultCacheMaxEntries
+	}
+	if c.fetchTimeout <= 0 {
+		c.fetchTimeout = defaultCacheFetchTimeout
+	}
+	return c
+}
+
+// Repository returns the wrapped repository for uncached reads. Writes made
+// through it do not invalidate the cache.
+func (c *CachedRepository[T]) Repository() *Repository[T] {
+	return c.repo
+}
+
+// Get returns the record with id, from the cache when possible.
+func (c *CachedRepository[T]) Get(ctx context.Context, id string, opts ...GetOptions) (*T, error) {
+	key, err := cacheKey("get", id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.load(ctx, key, id, func(ctx context.Context) (any, error) {
+		return c.raw.Get(ctx, id, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out T
+	if err := json.Unmarshal(*value.(*json.RawMessage), &out); err != nil {
+		return nil, fmt.Errorf("decode cached record: %w", err)
+	}
+	return &out, nil
+}
+
+// List returns a page of records, from the cache when possible.
+func (c *CachedRepository[T]) List(ctx context.Context, opts ListOptions) (*ListResult[T], error) {
+	key, err := cacheKey("list", "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.load(ctx, key, "", func(ctx context.Context) (any, error) {
+		return c.raw.List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := value.(*ListResult[json.RawMessage])
+	res := &ListResult[T]{
+		Items:      make([]T, len(page.Items)),
+		Page:       page.Page,
+		PerPage:    page.PerPage,
+		TotalItems: page.TotalItems,
+		TotalPages: page.TotalPages,
+	}
+	for i, item := range page.Items {
+		if err := json.Unmarshal(item, &res.Items[i]); err != nil {
+			return nil, fmt.Errorf("decode cached record: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// Create creates record and invalidates the cache.
+func (c *CachedRepository[T]) Create(ctx context.Context, record T) (*T, error) {
+	created, err := c.repo.Create(ctx, record)
+	c.invalidateRecord(created)
+	return created, err
+}
+
+// CreateWithFiles creates record with files and invalidates the cache.
+func (c *CachedRepository[T]) CreateWithFiles(ctx context.Context, record T, files *FileUpload) (*T, error) {
+	created, err := c.repo.CreateWithFiles(ctx, record, files)
+	c.invalidateRecord(created)
+	return created, err
+}
+
+// Update updates the record with id and invalidates it.
+func (c *CachedRepository[T]) Update(ctx context.Context, id string, record T) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.Update(ctx, id, record)
+}
+
+// UpdateWithFiles updates the record with id and its files and invalidates it.
+func (c *CachedRepository[T]) UpdateWithFiles(ctx context.Context, id string, record T, files *FileUpload) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.UpdateWithFiles(ctx, id, record, files)
+}
+
+// Patch applies patch to the record with id and invalidates it.
+func (c *CachedRepository[T]) Patch(ctx context.Context, id string, patch *FieldPatch) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.Patch(ctx, id, patch)
+}
+
+// UpdateFields sends the named fields of record and invalidates it.
+func (c *CachedRepository[T]) UpdateFields(ctx context.Context, id string, record T, fields ...string) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.UpdateFields(ctx, id, record, fields...)
+}
+
+// UpdateChanged sends the fields that differ between before and after and invalidates the record.
+func (c *CachedRepository[T]) UpdateChanged(ctx context.Context, id string, before, after T) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.UpdateChanged(ctx, id, before, after)
+}
+
+// UpdateIfUnchanged updates the record with id if its version matches expected and invalidates it.
+func (c *CachedRepository[T]) UpdateIfUnchanged(ctx context.Context, id, expected string, record T) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.UpdateIfUnchanged(ctx, id, expected, record)
+}
+
+// UpdateWithRetry applies fn to the current record until the update succeeds and invalidates it.
+func (c *CachedRepository[T]) UpdateWithRetry(ctx context.Context, id string, maxAttempts int, fn func(*T) error) (*T, error) {
+	defer c.Invalidate(id)
+	return c.repo.UpdateWithRetry(ctx, id, maxAttempts, fn)
+}
+
+// Upsert creates or updates record and invalidates it.
+func (c *CachedRepository[T]) Upsert(ctx context.Context, record T) (*T, error) {
+	defer c.invalidateRecord(&record)
+	return c.repo.Upsert(ctx, record)
+}
+
+// UpsertBy updates the record matching filter or creates record, and invalidates the cache.
+func (c *CachedRepository[T]) UpsertBy(ctx context.Context, filter string, record T) (*T, error) {
+	saved, err := c.repo.UpsertBy(ctx, filter, record)
+	c.invalidateRecord(saved)
+	return saved, err
+}
+
+// Delete deletes the record with id and invalidates it.
+func (c *CachedRepository[T]) Delete(ctx context.Context, id string) error {
+	defer c.Invalidate(id)
+	return c.repo.Delete(ctx, id)
+}
+
+// DeleteWhere deletes every record matching filter and empties the cache.
+func (c *CachedRepository[T]) DeleteWhere(ctx context.Context, filter string, opts BulkOptions) (*BulkResult, error) {
+	defer c.InvalidateAll()
+	return c.repo.DeleteWhere(ctx, filter, opts)
+}
+
+// UpdateWhere applies patch to every record matching filter and empties the cache.
+func (c *CachedRepository[T]) UpdateWhere(ctx context.Context, filter string, patch *FieldPatch, opts BulkOptions) (*BulkResult, error) {
+	defer c.InvalidateAll()
+	return c.repo.UpdateWhere(ctx, filter, patch, opts)
+}
+
+// Invalidate drops cached Get results for id and all cached List results.
+func (c *CachedRepository[T]) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*cacheEntry); entry.id == "" || entry.id == id {
+			c.removeElement(e)
+		}
+		e = next
+	}
+	// Fetches started before the write may return the old record; later
+	// callers start a fresh fetch instead of joining them.
+	maps.DeleteFunc(c.flights, func(_ string, flight *cacheFlight) bool {
+		return flight.id == "" || flight.id == id
+	})
+}
+
+// InvalidateAll empties the cache.
+func (c *CachedRepository[T]) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	clear(c.flights)
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted.
+func (c *CachedRepository[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// invalidateRecord invalidates the record's id, or the whole cache when the id
+// is unknown, e.g. because a write failed after it may have been applied.
+func (c *CachedRepository[T]) invalidateRecord(record *T) {
+	if record == nil {
+		c.InvalidateAll()
+		return
+	}
+	values, err := recordFields(record)
+	if err != nil {
+		c.InvalidateAll()
+		return
+	}
+	if id := stringField(values, "id"); id != "" {
+		c.Invalidate(id)
+		return
+	}
+	c.InvalidateAll()
+}
+
+// load returns the cached value for key or calls fetch, sharing the call with
+// concurrent callers for the same key. The shared fetch runs detached from the
+// callers' contexts, bounded by the fetch timeout; each caller stops waiting
+// when its own context is done.
+func (c *CachedRepository[T]) load(ctx context.Context, key, id string, fetch func(context.Context) (any, error)) (any, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		if c.now().Before(entry.expires) {
+			c.lru.MoveToFront(e)
+			c.mu.Unlock()
+			return entry.value, entry.err
+		}
+		c.removeElement(e)
+	}
+	flight, ok := c.flights[key]
+	if !ok {
+		flight = &cacheFlight{id: id, done: make(chan struct{})}
+		c.flights[key] = flight
+		go c.fetch(context.WithoutCancel(ctx), key, id, c.gen, flight, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-flight.done:
+		return flight.value, flight.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch runs a shared fetch and stores its result.
+func (c *CachedRepository[T]) fetch(ctx context.Context, key, id string, gen uint64, flight *cacheFlight, fetch func(context.Context) (any, error)) {
+	defer func() {
+		if p := recover(); p != nil {
+			flight.value, flight.err = nil, fmt.Errorf("cache fetch panicked: %v", p)
+		}
+
+		c.mu.Lock()
+		if c.flights[key] == flight {
+			delete(c.flights, key)
+		}
+		// Skip storing results that raced with an invalidation.
+		if gen == c.gen {
+			c.store(key, id, flight.value, flight.err)
+		}
+		c.mu.Unlock()
+		close(flight.done)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.fetchTimeout)
+	defer cancel()
+	flight.value, flight.err = fetch(ctx)
+}
+
+// store caches a result. Callers must hold c.mu.
+func (c *CachedRepository[T]) store(key, id string, value any, err error) {
+	ttl := c.ttl
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) || c.negativeTTL < 0 {
+			return
+		}
+		ttl = c.negativeTTL
+		value = nil
+	}
+
+	entry := &cacheEntry{key: key, id: id, value: value, err: err, expires: c.now().Add(ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// removeElement drops e from the cache. Callers must hold c.mu.
+func (c *CachedRepository[T]) removeElement(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*cacheEntry).key)
+}
+
+func cacheKey(kind, id string, opts any) (string, error) {
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	return kind + ":" + id + ":" + string(data), nil
+}
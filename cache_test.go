@@ -0,0 +1,342 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedRepositoryGetCachesAndExpires(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/api/collections/test/records/abc":
+			_, _ = w.Write([]byte(`{"id":"abc","name":"cached"}`))
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]any{"message": "missing"})
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{TTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		got, err := cache.Get(context.Background(), "abc")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Name != "cached" {
+			t.Fatalf("unexpected record: %+v", got)
+		}
+		got.Name = "mutated by caller"
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("expected 2 requests, got %d", hits.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	got, err := cache.Get(context.Background(), "abc")
+	if err != nil || got.Name != "cached" {
+		t.Fatalf("Get after expiry: %+v, %v", got, err)
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("expected refetch after expiry, got %d requests", hits.Load())
+	}
+}
+
+func TestCachedRepositoryEvictsLeastRecentlyUsed(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"id":"x"}`))
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{MaxEntries: 2})
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b", "a", "c", "a"} {
+		if _, err := cache.Get(ctx, id); err != nil {
+			t.Fatalf("Get %s: %v", id, err)
+		}
+	}
+	if hits.Load() != 3 || cache.Len() != 2 {
+		t.Fatalf("expected 3 requests and 2 entries, got %d and %d", hits.Load(), cache.Len())
+	}
+
+	if _, err := cache.Get(ctx, "b"); err != nil {
+		t.Fatalf("Get b: %v", err)
+	}
+	if hits.Load() != 4 {
+		t.Fatalf("expected b to have been evicted, got %d requests", hits.Load())
+	}
+}
+
+func TestCachedRepositorySharesConcurrentMisses(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		writeJSON(w, http.StatusOK, map[string]any{"items": []map[string]any{{"id": "a"}}, "page": 1, "perPage": 30, "totalItems": 1, "totalPages": 1})
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := cache.List(context.Background(), ListOptions{Filter: "x=1"})
+			if err != nil || len(res.Items) != 1 {
+				t.Errorf("List: %+v, %v", res, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if hits.Load() != 1 {
+		t.Fatalf("expected a single request, got %d", hits.Load())
+	}
+}
+
+func TestCachedRepositoryInvalidatesOnWrites(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets.Add(1)
+			_, _ = w.Write([]byte(`{"id":"abc","name":"v"}`))
+		case http.MethodPatch:
+			_, _ = w.Write([]byte(`{"id":"abc","name":"v2"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{})
+	ctx := context.Background()
+
+	get := func() {
+		t.Helper()
+		if _, err := cache.Get(ctx, "abc"); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+
+	get()
+	get()
+	if _, err := cache.Update(ctx, "abc", testRecord{Name: "v2"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	get()
+	if _, err := cache.Patch(ctx, "abc", Patch().Set("name", "v3")); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	get()
+	if err := cache.Delete(ctx, "abc"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	get()
+	cache.Invalidate("abc")
+	get()
+	cache.InvalidateAll()
+	get()
+
+	if gets.Load() != 6 {
+		t.Fatalf("expected 6 fetches, got %d", gets.Load())
+	}
+}
+
+func TestCachedRepositoryReturnsIndependentCopies(t *testing.T) {
+	type taggedRecord struct {
+		ID   string         `json:"id"`
+		Tags []string       `json:"tags"`
+		Meta map[string]any `json:"meta"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"abc","tags":["a","b"],"meta":{"k":"v"}}`))
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[taggedRecord](newTestClient(t, server), "test"), CacheOptions{})
+	ctx := context.Background()
+
+	first, err := cache.Get(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	first.Tags[0] = "mutated"
+	first.Meta["k"] = "mutated"
+
+	second, err := cache.Get(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if second.Tags[0] != "a" || second.Meta["k"] != "v" {
+		t.Fatalf("cached record was modified through a previous result: %+v", second)
+	}
+}
+
+func TestCachedRepositoryWaiterSurvivesFirstCallerCancel(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"id":"abc","name":"shared"}`))
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{})
+
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cache.Get(firstCtx, "abc")
+		firstErr <- err
+	}()
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	secondDone := make(chan *testRecord, 1)
+	go func() {
+		got, err := cache.Get(context.Background(), "abc")
+		if err != nil {
+			t.Errorf("second Get: %v", err)
+		}
+		secondDone <- got
+	}()
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the first caller to see its cancellation, got %v", err)
+	}
+	close(release)
+	if got := <-secondDone; got == nil || got.Name != "shared" {
+		t.Fatalf("unexpected record for waiter: %+v", got)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected a single request, got %d", hits.Load())
+	}
+}
+
+func TestCachedRepositoryInvalidatesDespiteFailingHooks(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets.Add(1)
+			_, _ = w.Write([]byte(`{"id":"abc","name":"v"}`))
+		case http.MethodPost:
+			writeJSON(w, http.StatusOK, []map[string]any{{"status": 200, "body": map[string]any{"id": "abc"}}})
+		default:
+			_, _ = w.Write([]byte(`{"id":"abc","name":"v2"}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	repo.AfterUpdate(func(ctx context.Context, record *testRecord) error {
+		return errors.New("publish failed")
+	})
+	cache := NewCachedRepository(repo, CacheOptions{})
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "abc"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := cache.Update(ctx, "abc", testRecord{Name: "v2"}); err == nil {
+		t.Fatalf("expected the after hook error")
+	}
+	if _, err := cache.Get(ctx, "abc"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := cache.Upsert(ctx, testRecord{ID: "abc", Name: "v3"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if _, err := cache.Get(ctx, "abc"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if gets.Load() != 3 {
+		t.Fatalf("expected 3 fetches, got %d", gets.Load())
+	}
+}
+
+func TestCachedRepositoryGetAfterWriteSkipsEarlierFetch(t *testing.T) {
+	var (
+		gets    atomic.Int32
+		current atomic.Value
+	)
+	current.Store("old")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			name := current.Load().(string)
+			if gets.Add(1) == 1 {
+				<-release
+			}
+			writeJSON(w, http.StatusOK, testRecord{ID: "abc", Name: name})
+		case http.MethodPatch:
+			current.Store("new")
+			writeJSON(w, http.StatusOK, testRecord{ID: "abc", Name: "new"})
+		}
+	}))
+	defer server.Close()
+
+	cache := NewCachedRepository(NewRepository[testRecord](newTestClient(t, server), "test"), CacheOptions{})
+	ctx := context.Background()
+
+	before := make(chan *testRecord, 1)
+	go func() {
+		got, err := cache.Get(ctx, "abc")
+		if err != nil {
+			t.Errorf("first Get: %v", err)
+		}
+		before <- got
+	}()
+	for gets.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := cache.Update(ctx, "abc", testRecord{Name: "new"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := cache.Get(ctx, "abc")
+	if err != nil || got.Name != "new" {
+		t.Fatalf("Get after write: %+v, %v", got, err)
+	}
+
+	close(release)
+	if got := <-before; got == nil || got.Name != "old" {
+		t.Fatalf("unexpected record for the earlier Get: %+v", got)
+	}
+	if got, err := cache.Get(ctx, "abc"); err != nil || got.Name != "new" {
+		t.Fatalf("expected the earlier fetch not to be cached, got %+v, %v", got, err)
+	}
+	if gets.Load() != 2 {
+		t.Fatalf("expected 2 fetches, got %d", gets.Load())
+	}
+}
@@ -27,11 +27,29 @@ type Client interface {
 	AuthenticateSuperuser(creds Credentials) (AuthenticatedClient, error)
 }
 
+// ProviderAuthenticator is implemented by clients that can authenticate with
+// a CredentialsProvider, which is consulted again whenever the token has to
+// be renewed. The client returned by NewClient implements it. ctx is passed
+// to the first Retrieve; later ones get the context of the request that
+// triggers the renewal.
+type ProviderAuthenticator interface {
+	AuthenticateUserWithProvider(ctx context.Context, provider CredentialsProvider) (AuthenticatedClient, error)
+	AuthenticateSuperuserWithProvider(ctx context.Context, provider CredentialsProvider) (AuthenticatedClient, error)
+}
+
 // AuthenticatedClient provides authenticated HTTP access to PocketBase.
 type AuthenticatedClient interface {
 	Do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error)
 }
 
+// HeaderDoer is implemented by authenticated clients that can send requests
+// with extra headers and a streamed body. File uploads need it to send their
+// multipart Content-Type; wrappers around an AuthenticatedClient should
+// implement it as well to support them.
+type HeaderDoer interface {
+	DoWithHeader(ctx context.Context, method, path string, header http.Header, body io.Reader) (*http.Response, error)
+}
+
 // ClientOption configures optional Client settings.
 type ClientOption func(*client)
 
@@ -114,20 +132,65 @@ const (
 
 // AuthenticateUser authenticates using the users collection endpoint.
 func (c *client) AuthenticateUser(creds Credentials) (AuthenticatedClient, error) {
-	return c.authenticate(creds, userAuthEndpoint)
+	return c.authenticate(context.Background(), creds, userAuthEndpoint)
 }
 
 // AuthenticateSuperuser authenticates using the superuser endpoint.
 func (c *client) AuthenticateSuperuser(creds Credentials) (AuthenticatedClient, error) {
-	return c.authenticate(creds, superuserAuthEndpoint)
+	return c.authenticate(context.Background(), creds, superuserAuthEndpoint)
+}
+
+// AuthenticateUserWithProvider authenticates against the users collection,
+// consulting provider again whenever the token has to be renewed.
+func (c *client) AuthenticateUserWithProvider(ctx context.Context, provider CredentialsProvider) (AuthenticatedClient, error) {
+	return c.authenticate(ctx, provider, userAuthEndpoint)
+}
+
+// AuthenticateSuperuserWithProvider authenticates as a superuser,
+// consulting provider again whenever the token has to be renewed.
+func (c *client) AuthenticateSuperuserWithProvider(ctx context.Context, provider CredentialsProvider) (AuthenticatedClient, error) {
+	return c.authenticate(ctx, provider, superuserAuthEndpoint)
+}
+
+func (c *client) authenticate(ctx context.Context, provider CredentialsProvider, endpoint string) (AuthenticatedClient, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if provider == nil {
+		return nil, errors.New("credentials provider is required")
+	}
+
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	token, err := c.requestToken(ctx, creds, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := time.Now().Add(23 * time.Hour)
+	if c.logger != nil {
+		c.logger.Info("authenticated with PocketBase", "expires", expiry)
+	}
+
+	return &authenticatedClient{
+		client:       c,
+		token:        token,
+		tokenExpires: expiry,
+		creds:        provider,
+		authEndpoint: endpoint,
+	}, nil
 }
 
-func (c *client) authenticate(creds Credentials, endpoint string) (AuthenticatedClient, error) {
+// requestToken exchanges credentials for an auth token at the given endpoint.
+func (c *client) requestToken(ctx context.Context, creds Credentials, endpoint string) (string, error) {
 	if strings.TrimSpace(creds.Email) == "" {
-		return nil, errors.New("email is required")
+		return "", errors.New("email is required")
 	}
 	if creds.Password == "" {
-		return nil, errors.New("password is required")
+		return "", errors.New("password is required")
 	}
 
 	payload := map[string]string{
@@ -137,53 +200,41 @@ func (c *client) authenticate(creds Credentials, endpoint string) (Authenticated
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
-		return nil, fmt.Errorf("encode auth payload: %w", err)
+		return "", fmt.Errorf("encode auth payload: %w", err)
 	}
 
 	url := c.baseURL + endpoint
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
 	if err != nil {
-		return nil, fmt.Errorf("build auth request: %w", err)
+		return "", fmt.Errorf("build auth request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("authentication request failed: %w", err)
+		return "", fmt.Errorf("authentication request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read auth response: %w", err)
+		return "", fmt.Errorf("read auth response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, mapHTTPError(resp.StatusCode, body)
+		return "", mapHTTPError(resp.StatusCode, body)
 	}
 
 	var authResp struct {
 		Token string `json:"token"`
 	}
 	if err := json.Unmarshal(body, &authResp); err != nil {
-		return nil, fmt.Errorf("parse auth response: %w", err)
+		return "", fmt.Errorf("parse auth response: %w", err)
 	}
 	if authResp.Token == "" {
-		return nil, errors.New("authentication succeeded but token missing")
-	}
-
-	expiry := time.Now().Add(23 * time.Hour)
-	if c.logger != nil {
-		c.logger.Info("authenticated with PocketBase", "expires", expiry)
+		return "", errors.New("authentication succeeded but token missing")
 	}
-
-	return &authenticatedClient{
-		client:       c,
-		token:        authResp.Token,
-		tokenExpires: expiry,
-		creds:        creds,
-		authEndpoint: endpoint,
-	}, nil
+	return authResp.Token, nil
 }
 
 // authenticatedClient is the implementation of AuthenticatedClient.
@@ -191,7 +242,7 @@ type authenticatedClient struct {
 	client       *client
 	token        string
 	tokenExpires time.Time
-	creds        Credentials
+	creds        CredentialsProvider
 	authEndpoint string
 	authMutex    sync.Mutex
 	tokenMutex   sync.RWMutex
@@ -216,7 +267,7 @@ func (ac *authenticatedClient) Do(ctx context.Context, method, path string, body
 	attempts := ac.client.maxRetries
 
 	for attempt := 0; attempt <= attempts; attempt++ {
-		if err := ac.ensureAuthenticated(); err != nil {
+		if err := ac.ensureAuthenticated(ctx); err != nil {
 			return nil, err
 		}
 
@@ -267,66 +318,115 @@ func (ac *authenticatedClient) Do(ctx context.Context, method, path string, body
 	return nil, errors.New("request failed after retries")
 }
 
-func (ac *authenticatedClient) ensureAuthenticated() error {
-	if ac.tokenValid() {
-		return nil
-	}
-	ac.authMutex.Lock()
-	defer ac.authMutex.Unlock()
+// streamer is implemented by clients that can open long-lived streaming
+// requests, such as event streams and downloads, which must not be cut off by
+// the HTTP client timeout.
+type streamer interface {
+	stream(ctx context.Context, path string, header http.Header) (*http.Response, error)
+}
 
-	if ac.tokenValid() {
-		return nil
+// stream opens an authenticated GET request without the client timeout and
+// without retries; the caller owns the response body.
+func (ac *authenticatedClient) stream(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	req, err := ac.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
 	}
-	return ac.reauthenticate()
-}
 
-func (ac *authenticatedClient) reauthenticate() error {
-	payload := map[string]string{
-		"identity": ac.creds.Email,
-		"password": ac.creds.Password,
+	hc := *ac.client.httpClient
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		ac.clearToken()
 	}
+	return resp, nil
+}
 
-	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
-		return fmt.Errorf("encode auth payload: %w", err)
+// DoWithHeader executes an authenticated request with the given headers.
+// Unlike Do it streams body instead of buffering it, so the request is sent
+// once and not retried.
+func (ac *authenticatedClient) DoWithHeader(ctx context.Context, method, path string, header http.Header, body io.Reader) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	url := ac.client.baseURL + ac.authEndpoint
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &buf)
+	req, err := ac.newRequest(ctx, method, path, body)
 	if err != nil {
-		return fmt.Errorf("build auth request: %w", err)
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := ac.client.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("authentication request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		ac.clearToken()
+	}
+	return resp, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// newRequest authenticates if needed and builds a request carrying the token.
+func (ac *authenticatedClient) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	if err := ac.ensureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, ac.client.baseURL+"/"+strings.TrimLeft(path, "/"), body)
 	if err != nil {
-		return fmt.Errorf("read auth response: %w", err)
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if token := ac.readToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
+	return req, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		ac.clearToken()
-		return mapHTTPError(resp.StatusCode, body)
+func (ac *authenticatedClient) ensureAuthenticated(ctx context.Context) error {
+	if ac.tokenValid() {
+		return nil
 	}
+	ac.authMutex.Lock()
+	defer ac.authMutex.Unlock()
 
-	var authResp struct {
-		Token string `json:"token"`
+	if ac.tokenValid() {
+		return nil
 	}
-	if err := json.Unmarshal(body, &authResp); err != nil {
-		return fmt.Errorf("parse auth response: %w", err)
+	return ac.reauthenticate(ctx)
+}
+
+// reauthenticate fetches a fresh token, consulting the credentials provider
+// so rotated secrets are picked up without restarting the process. ctx is the
+// context of the request that needs the token.
+func (ac *authenticatedClient) reauthenticate(ctx context.Context) error {
+	if ac.creds == nil {
+		ac.clearToken()
+		return errors.New("credentials provider is required")
 	}
-	if authResp.Token == "" {
-		return errors.New("authentication succeeded but token missing")
+
+	creds, err := ac.creds.Retrieve(ctx)
+	if err != nil {
+		ac.clearToken()
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	token, err := ac.client.requestToken(ctx, creds, ac.authEndpoint)
+	if err != nil {
+		ac.clearToken()
+		return err
 	}
 
 	expiry := time.Now().Add(23 * time.Hour)
 	ac.tokenMutex.Lock()
-	ac.token = authResp.Token
+	ac.token = token
 	ac.tokenExpires = expiry
 	ac.tokenMutex.Unlock()
 
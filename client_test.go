@@ -115,7 +115,7 @@ func TestAuthenticateSuccessAndFailure(t *testing.T) {
 	}
 
 	// success
-	if err := ac.reauthenticate(); err != nil {
+	if err := ac.reauthenticate(context.Background()); err != nil {
 		t.Fatalf("authenticate success: %v", err)
 	}
 	if ac.readToken() != "tok1" {
@@ -126,7 +126,7 @@ func TestAuthenticateSuccessAndFailure(t *testing.T) {
 	}
 
 	// failure clears token
-	if err := ac.reauthenticate(); !errors.Is(err, ErrUnauthorized) {
+	if err := ac.reauthenticate(context.Background()); !errors.Is(err, ErrUnauthorized) {
 		t.Fatalf("expected ErrUnauthorized, got %v", err)
 	}
 	if ac.readToken() != "" {
@@ -158,7 +158,7 @@ func TestEnsureAuthenticatedRefreshOnExpiry(t *testing.T) {
 		tokenExpires: time.Now().Add(-time.Minute),
 	}
 
-	if err := client.ensureAuthenticated(); err != nil {
+	if err := client.ensureAuthenticated(context.Background()); err != nil {
 		t.Fatalf("ensureAuthenticated: %v", err)
 	}
 	if authCalls != 1 {
@@ -245,12 +245,12 @@ func TestEnsureAuthenticatedSingleFlight(t *testing.T) {
 
 	go func() {
 		<-start
-		_ = client.ensureAuthenticated()
+		_ = client.ensureAuthenticated(context.Background())
 		done <- struct{}{}
 	}()
 	go func() {
 		<-start
-		_ = client.ensureAuthenticated()
+		_ = client.ensureAuthenticated(context.Background())
 		done <- struct{}{}
 	}()
 
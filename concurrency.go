@@ -0,0 +1,161 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrStaleRecord is returned when a conditional update finds that the record changed on the server.
+var ErrStaleRecord = errors.New("stale record")
+
+const defaultVersionField = "updated"
+
+// StaleRecordError carries the current server copy of a record that failed a
+// conditional update. It matches ErrStaleRecord with errors.Is.
+type StaleRecordError[T any] struct {
+	Current *T
+}
+
+func (e *StaleRecordError[T]) Error() string {
+	return ErrStaleRecord.Error()
+}
+
+func (e *StaleRecordError[T]) Unwrap() error {
+	return ErrStaleRecord
+}
+
+// VersionOf returns the concurrency token of record: its "updated" timestamp,
+// or the value of the field configured with WithVersionField.
+func (r *Repository[T]) VersionOf(record T) (string, error) {
+	values, err := recordFields(record)
+	if err != nil {
+		return "", err
+	}
+	raw, ok := values[r.versionField()]
+	if !ok {
+		return "", fmt.Errorf("record has no %s field", r.versionField())
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// UpdateIfUnchanged updates the record only if its version still equals expected,
+// as returned by VersionOf. Otherwise it returns a *StaleRecordError holding the
+// current server copy.
+//
+// With WithVersionField the write itself is conditional: the record is sent
+// with the version set to expected+1, and a collection update rule such as
+//
+//	@request.body.version > version
+//
+// makes PocketBase reject every write that lost a race against a concurrent
+// one. Such rejections are reported as a *StaleRecordError as well. With the
+// default "updated" field, which the client cannot send, the version is only
+// compared right before the write, so two concurrent writers can still both
+// succeed; use a version field where lost updates must be prevented.
+func (r *Repository[T]) UpdateIfUnchanged(ctx context.Context, id, expected string, record T) (*T, error) {
+	current, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkVersion(current, expected); err != nil {
+		return nil, err
+	}
+
+	field := r.versionField()
+	var next json.RawMessage
+	if field != defaultVersionField {
+		version, err := strconv.ParseInt(expected, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("version %q is not an integer: %w", expected, err)
+		}
+		next = json.RawMessage(strconv.FormatInt(version+1, 10))
+	}
+
+	return r.hookedUpdate(ctx, id, record, func(record T) (*T, error) {
+		payload, err := recordFields(record)
+		if err != nil {
+			return nil, err
+		}
+		delete(payload, field)
+		if next != nil {
+			payload[field] = next
+		}
+
+		updated, err := r.patch(ctx, id, payload)
+		if next != nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden)) {
+			// The update rule hides the record from writes that lost the race.
+			if current, getErr := r.Get(ctx, id); getErr == nil {
+				if staleErr := r.checkVersion(current, expected); staleErr != nil {
+					return nil, staleErr
+				}
+			}
+		}
+		return updated, err
+	})
+}
+
+// checkVersion returns a *StaleRecordError unless current has the expected version.
+func (r *Repository[T]) checkVersion(current *T, expected string) error {
+	version, err := r.VersionOf(*current)
+	if err != nil {
+		return err
+	}
+	if version != expected {
+		return &StaleRecordError[T]{Current: current}
+	}
+	return nil
+}
+
+// UpdateWithRetry runs a read-modify-write cycle: it loads the record, lets fn
+// modify it and writes it back with UpdateIfUnchanged. On a concurrent
+// modification fn is called again with the fresh server copy, up to maxAttempts
+// times in total.
+func (r *Repository[T]) UpdateWithRetry(ctx context.Context, id string, maxAttempts int, fn func(*T) error) (*T, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	current, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		expected, err := r.VersionOf(*current)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(current); err != nil {
+			return nil, err
+		}
+
+		updated, err := r.UpdateIfUnchanged(ctx, id, expected, *current)
+		if err == nil {
+			return updated, nil
+		}
+
+		var stale *StaleRecordError[T]
+		if !errors.As(err, &stale) {
+			return nil, err
+		}
+		lastErr = err
+		current = stale.Current
+	}
+	return nil, lastErr
+}
+
+func (r *Repository[T]) versionField() string {
+	if r.opts.versionField != "" {
+		return r.opts.versionField
+	}
+	return defaultVersionField
+}
@@ -0,0 +1,140 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type versionedRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+// newVersionedServer serves a single record and enforces the update rule
+// "@request.body.version > version", answering 404 like PocketBase when it fails.
+// bumpOnGet simulates a concurrent writer for the first n reads.
+func newVersionedServer(t *testing.T, bumpOnGet int) (*httptest.Server, *versionedRecord) {
+	t.Helper()
+	rec := &versionedRecord{ID: "r1", Name: "start", Version: 1}
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			if gets > 1 && gets <= bumpOnGet+1 {
+				rec.Version++
+				rec.Name = "concurrent-" + strconv.Itoa(rec.Version)
+			}
+			writeJSON(w, http.StatusOK, rec)
+		case http.MethodPatch:
+			var payload map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var version int
+			if err := json.Unmarshal(payload["version"], &version); err != nil {
+				t.Fatalf("expected the next version in the body, got %s", payload["version"])
+			}
+			if version <= rec.Version {
+				writeJSON(w, http.StatusNotFound, map[string]any{"message": "The requested resource wasn't found."})
+				return
+			}
+			_ = json.Unmarshal(payload["name"], &rec.Name)
+			rec.Version = version
+			writeJSON(w, http.StatusOK, rec)
+		}
+	}))
+	return server, rec
+}
+
+func TestRepositoryUpdateIfUnchangedDetectsStaleVersion(t *testing.T) {
+	server, _ := newVersionedServer(t, 0)
+	defer server.Close()
+
+	repo := NewRepository[versionedRecord](newTestClient(t, server), "test", WithVersionField("version"))
+	ctx := context.Background()
+
+	_, err := repo.UpdateIfUnchanged(ctx, "r1", "7", versionedRecord{Name: "mine"})
+	var stale *StaleRecordError[versionedRecord]
+	if !errors.As(err, &stale) || !errors.Is(err, ErrStaleRecord) {
+		t.Fatalf("expected StaleRecordError, got %v", err)
+	}
+	if stale.Current == nil || stale.Current.Version != 1 {
+		t.Fatalf("expected current copy, got %+v", stale.Current)
+	}
+
+	updated, err := repo.UpdateIfUnchanged(ctx, "r1", "1", versionedRecord{Name: "mine"})
+	if err != nil {
+		t.Fatalf("UpdateIfUnchanged: %v", err)
+	}
+	if updated.Name != "mine" || updated.Version != 2 {
+		t.Fatalf("unexpected updated record: %+v", updated)
+	}
+}
+
+func TestRepositoryUpdateIfUnchangedRejectedByUpdateRule(t *testing.T) {
+	server, rec := newVersionedServer(t, 0)
+	defer server.Close()
+
+	repo := NewRepository[versionedRecord](newTestClient(t, server), "test", WithVersionField("version"))
+	ctx := context.Background()
+
+	// A concurrent writer commits version 2 between our read and our write.
+	repo.BeforeUpdate(func(ctx context.Context, id string, record *versionedRecord) error {
+		rec.Version, rec.Name = 2, "theirs"
+		return nil
+	})
+
+	_, err := repo.UpdateIfUnchanged(ctx, "r1", "1", versionedRecord{Name: "mine"})
+	var stale *StaleRecordError[versionedRecord]
+	if !errors.As(err, &stale) {
+		t.Fatalf("expected StaleRecordError, got %v", err)
+	}
+	if stale.Current.Name != "theirs" || rec.Name != "theirs" {
+		t.Fatalf("lost update: current %+v, server %+v", stale.Current, rec)
+	}
+}
+
+func TestRepositoryUpdateWithRetry(t *testing.T) {
+	server, rec := newVersionedServer(t, 1)
+	defer server.Close()
+
+	repo := NewRepository[versionedRecord](newTestClient(t, server), "test", WithVersionField("version"))
+
+	calls := 0
+	updated, err := repo.UpdateWithRetry(context.Background(), "r1", 3, func(v *versionedRecord) error {
+		calls++
+		v.Name = v.Name + "+edited"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateWithRetry: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected callback to run twice, got %d", calls)
+	}
+	if updated.Name != "concurrent-2+edited" || rec.Version != 3 {
+		t.Fatalf("unexpected result: %+v (server %+v)", updated, rec)
+	}
+}
+
+func TestRepositoryVersionOfDefaultsToUpdated(t *testing.T) {
+	type record struct {
+		ID      string `json:"id"`
+		Updated string `json:"updated"`
+	}
+	repo := NewRepository[record](nil, "test")
+	got, err := repo.VersionOf(record{ID: "x", Updated: "2024-01-01 10:00:00.000Z"})
+	if err != nil {
+		t.Fatalf("VersionOf: %v", err)
+	}
+	if got != "2024-01-01 10:00:00.000Z" {
+		t.Fatalf("unexpected version %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies credentials whenever the client needs to (re)authenticate.
+// Implementations must be safe for concurrent use.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// Retrieve returns the static credentials, allowing Credentials to be used as a CredentialsProvider.
+func (c Credentials) Retrieve(context.Context) (Credentials, error) {
+	return c, nil
+}
+
+// EnvCredentials reads credentials from the named environment variables on every call.
+func EnvCredentials(emailVar, passwordVar string) CredentialsProvider {
+	return envCredentials{emailVar: emailVar, passwordVar: passwordVar}
+}
+
+type envCredentials struct {
+	emailVar    string
+	passwordVar string
+}
+
+func (p envCredentials) Retrieve(context.Context) (Credentials, error) {
+	email := strings.TrimSpace(os.Getenv(p.emailVar))
+	if email == "" {
+		return Credentials{}, fmt.Errorf("environment variable %s is empty", p.emailVar)
+	}
+	password := os.Getenv(p.passwordVar)
+	if password == "" {
+		return Credentials{}, fmt.Errorf("environment variable %s is empty", p.passwordVar)
+	}
+	return Credentials{Email: email, Password: password}, nil
+}
+
+// FileCredentials reads the email and password from separate files, as mounted
+// for example from a Kubernetes secret. Files are re-read only when their
+// modification time or size changes; trailing newlines are ignored.
+func FileCredentials(emailPath, passwordPath string) CredentialsProvider {
+	return &fileCredentials{
+		email:    watchedFile{path: emailPath},
+		password: watchedFile{path: passwordPath},
+	}
+}
+
+type fileCredentials struct {
+	mu       sync.Mutex
+	email    watchedFile
+	password watchedFile
+}
+
+func (p *fileCredentials) Retrieve(context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	email, err := p.email.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+	password, err := p.password.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	creds := Credentials{Email: strings.TrimSpace(email), Password: password}
+	if creds.Email == "" {
+		return Credentials{}, errors.New("email file is empty")
+	}
+	if creds.Password == "" {
+		return Credentials{}, errors.New("password file is empty")
+	}
+	return creds, nil
+}
+
+// watchedFile caches file contents until the file changes on disk.
+type watchedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+	content string
+	loaded  bool
+}
+
+func (f *watchedFile) read() (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("stat credentials file: %w", err)
+	}
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.content, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("read credentials file: %w", err)
+	}
+
+	f.content = strings.TrimRight(string(data), "\r\n")
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.loaded = true
+	return f.content, nil
+}
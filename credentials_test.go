@@ -0,0 +1,145 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("PB_TEST_EMAIL", "admin@example.com")
+	t.Setenv("PB_TEST_PASSWORD", "secret")
+
+	provider := EnvCredentials("PB_TEST_EMAIL", "PB_TEST_PASSWORD")
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if creds.Email != "admin@example.com" || creds.Password != "secret" {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+
+	t.Setenv("PB_TEST_PASSWORD", "")
+	if _, err := provider.Retrieve(context.Background()); err == nil {
+		t.Fatalf("expected error for empty password")
+	}
+}
+
+func TestFileCredentialsReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	emailPath := filepath.Join(dir, "email")
+	passwordPath := filepath.Join(dir, "password")
+	writeFile(t, emailPath, "admin@example.com\n")
+	writeFile(t, passwordPath, "first\n")
+
+	provider := FileCredentials(emailPath, passwordPath)
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if creds.Password != "first" {
+		t.Fatalf("expected first password, got %q", creds.Password)
+	}
+
+	writeFile(t, passwordPath, "rotated\n")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(passwordPath, future, future); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	creds, err = provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve after rotation: %v", err)
+	}
+	if creds.Password != "rotated" {
+		t.Fatalf("expected rotated password, got %q", creds.Password)
+	}
+}
+
+func TestReauthenticateUsesProvider(t *testing.T) {
+	var passwords []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		passwords = append(passwords, payload["password"])
+		_, _ = w.Write([]byte(`{"token":"tok"}`))
+	}))
+	defer ts.Close()
+
+	t.Setenv("PB_TEST_EMAIL", "admin@example.com")
+	t.Setenv("PB_TEST_PASSWORD", "first")
+
+	rawClient, err := NewClient(ts.URL, WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	authed, err := rawClient.(ProviderAuthenticator).AuthenticateSuperuserWithProvider(context.Background(), EnvCredentials("PB_TEST_EMAIL", "PB_TEST_PASSWORD"))
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	t.Setenv("PB_TEST_PASSWORD", "rotated")
+	ac := authed.(*authenticatedClient)
+	ac.clearToken()
+	if err := ac.ensureAuthenticated(context.Background()); err != nil {
+		t.Fatalf("ensureAuthenticated: %v", err)
+	}
+
+	if len(passwords) != 2 || passwords[0] != "first" || passwords[1] != "rotated" {
+		t.Fatalf("unexpected passwords sent: %v", passwords)
+	}
+}
+
+type ctxKey struct{}
+
+// recordingProvider returns fixed credentials and records the context value of each call.
+type recordingProvider struct {
+	seen []any
+}
+
+func (p *recordingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.seen = append(p.seen, ctx.Value(ctxKey{}))
+	return Credentials{Email: "admin@example.com", Password: "secret"}, nil
+}
+
+func TestProviderReceivesCallerContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token":"tok"}`))
+	}))
+	defer ts.Close()
+
+	rawClient, err := NewClient(ts.URL, WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	provider := &recordingProvider{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "authenticate")
+	authed, err := rawClient.(ProviderAuthenticator).AuthenticateUserWithProvider(ctx, provider)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	authed.(*authenticatedClient).clearToken()
+	ctx = context.WithValue(context.Background(), ctxKey{}, "request")
+	resp, err := authed.Do(ctx, http.MethodGet, "/api/health", nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(provider.seen) != 2 || provider.seen[0] != "authenticate" || provider.seen[1] != "request" {
+		t.Fatalf("unexpected contexts: %v", provider.seen)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,237 @@
+package pbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor string cannot be decoded or does not match the options.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const defaultCursorSortField = "created"
+
+// CursorOptions configures keyset pagination over (SortField, id).
+type CursorOptions struct {
+	// SortField is the field to paginate by; defaults to "created".
+	SortField  string
+	Descending bool
+	PerPage    int
+	Filter     string
+	Fields     []string
+	Expand     []string
+	// After resumes the scan after the position encoded by a previous CursorPage.Next.
+	After string
+}
+
+// CursorPage contains a page of items and the cursor to resume after it.
+type CursorPage[T any] struct {
+	Items []T
+	// Next is empty once the last page has been returned.
+	Next string
+}
+
+// cursorState is the decoded form of an opaque cursor string.
+type cursorState struct {
+	Field      string          `json:"f"`
+	Descending bool            `json:"d,omitempty"`
+	Value      json.RawMessage `json:"v"`
+	ID         json.RawMessage `json:"id"`
+}
+
+// ListCursor returns the page following opts.After using keyset pagination.
+// Unlike page-based listing it stays fast on large collections and does not
+// skip or repeat records when rows are inserted during a scan.
+func (r *Repository[T]) ListCursor(ctx context.Context, opts CursorOptions) (*CursorPage[T], error) {
+	field := strings.TrimSpace(opts.SortField)
+	if field == "" {
+		field = defaultCursorSortField
+	}
+	perPage := opts.PerPage
+	if perPage <= 0 {
+		perPage = defaultIterPerPage
+	}
+
+	filter := opts.Filter
+	if opts.After != "" {
+		state, err := decodeCursor(opts.After)
+		if err != nil {
+			return nil, err
+		}
+		if state.Field != field || state.Descending != opts.Descending {
+			return nil, fmt.Errorf("%w: cursor was created for sort %q", ErrInvalidCursor, state.Field)
+		}
+		after, err := keysetFilter(state)
+		if err != nil {
+			return nil, err
+		}
+		filter = And(filter, after)
+	}
+
+	sort := field + ",id"
+	if field == "id" {
+		sort = "id"
+	}
+	if opts.Descending {
+		sort = "-" + strings.ReplaceAll(sort, ",", ",-")
+	}
+
+	fields := opts.Fields
+	if len(fields) == 0 && !slices.Contains(r.autoFields, "*") {
+		fields = r.autoFields
+	}
+	if len(fields) > 0 {
+		fields = slices.Clone(fields)
+		for _, required := range []string{"id", field} {
+			if !slices.Contains(fields, required) {
+				fields = append(fields, required)
+			}
+		}
+	}
+
+	// The cursor is built from the raw item, so the sort value and id keep the
+	// exact representation the server compares against.
+	res, err := r.rawRepository().List(ctx, ListOptions{
+		Page:      1,
+		PerPage:   perPage,
+		Filter:    filter,
+		Sort:      sort,
+		Fields:    fields,
+		Expand:    opts.Expand,
+		SkipTotal: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CursorPage[T]{Items: make([]T, len(res.Items))}
+	for i, data := range res.Items {
+		if err := json.Unmarshal(data, &page.Items[i]); err != nil {
+			return nil, fmt.Errorf("decode record: %w", err)
+		}
+	}
+	if isLastPage(res, perPage) {
+		return page, nil
+	}
+
+	next, err := encodeCursor(res.Items[len(res.Items)-1], field, opts.Descending)
+	if err != nil {
+		return nil, err
+	}
+	page.Next = next
+	return page, nil
+}
+
+// CursorPages lazily walks pages with keyset pagination. Each page carries the
+// cursor to resume after it, so long exports can checkpoint their progress.
+func (r *Repository[T]) CursorPages(ctx context.Context, opts CursorOptions) iter.Seq2[*CursorPage[T], error] {
+	return func(yield func(*CursorPage[T], error) bool) {
+		for {
+			page, err := r.ListCursor(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) || page.Next == "" {
+				return
+			}
+			opts.After = page.Next
+		}
+	}
+}
+
+// encodeCursor builds the cursor after the raw item. View collections may use
+// numeric ids, so the id is kept as JSON like the sort value.
+func encodeCursor(item json.RawMessage, field string, descending bool) (string, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(item, &values); err != nil {
+		return "", fmt.Errorf("decode record: %w", err)
+	}
+
+	id := bytes.TrimSpace(values["id"])
+	if len(id) == 0 || string(id) == "null" || string(id) == `""` {
+		return "", errors.New("cursor pagination requires records with an id field")
+	}
+	value, ok := values[field]
+	if !ok {
+		return "", fmt.Errorf("cursor pagination requires records with a %s field", field)
+	}
+
+	state, err := json.Marshal(cursorState{Field: field, Descending: descending, Value: value, ID: id})
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(state), nil
+}
+
+func decodeCursor(cursor string) (cursorState, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return cursorState{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	var state cursorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return cursorState{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if state.Field == "" || len(state.ID) == 0 {
+		return cursorState{}, ErrInvalidCursor
+	}
+	return state, nil
+}
+
+// keysetFilter builds `field > v || (field = v && id > last)` (or < when descending).
+func keysetFilter(state cursorState) (string, error) {
+	after := Gt
+	if state.Descending {
+		after = Lt
+	}
+	id, err := filterLiteral(state.ID)
+	if err != nil {
+		return "", err
+	}
+	if state.Field == "id" {
+		return after("id", id), nil
+	}
+
+	literal, err := filterLiteral(state.Value)
+	if err != nil {
+		return "", err
+	}
+	return Or(
+		after(state.Field, literal),
+		And(state.Field+"="+literal, after("id", id)),
+	), nil
+}
+
+// filterLiteral renders a JSON value as a PocketBase filter literal.
+func filterLiteral(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		return quoteFilterValue(s), nil
+	}
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	switch v.(type) {
+	case float64, bool, nil:
+		return string(raw), nil
+	default:
+		return "", fmt.Errorf("%w: unsupported sort value %s", ErrInvalidCursor, raw)
+	}
+}
+
+func quoteFilterValue(value string) string {
+	return "'" + escapeFilterValue(value) + "'"
+}
@@ -0,0 +1,139 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type cursorRecord struct {
+	ID      string `json:"id"`
+	Created string `json:"created"`
+}
+
+func TestRepositoryCursorPages(t *testing.T) {
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("sort") != "created,id" {
+			t.Fatalf("unexpected sort: %s", q.Get("sort"))
+		}
+		filters = append(filters, q.Get("filter"))
+
+		var items []cursorRecord
+		switch len(filters) {
+		case 1:
+			items = []cursorRecord{{ID: "a", Created: "2024-01-01 00:00:00.000Z"}, {ID: "b", Created: "2024-01-02 00:00:00.000Z"}}
+		case 2:
+			items = []cursorRecord{{ID: "c", Created: "2024-01-03 00:00:00.000Z"}}
+		default:
+			t.Fatalf("unexpected extra request")
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 2, "totalItems": -1, "totalPages": -1})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[cursorRecord](newTestClient(t, server), "test")
+
+	var ids []string
+	var cursors []string
+	for page, err := range repo.CursorPages(context.Background(), CursorOptions{PerPage: 2, Filter: "status='ok'"}) {
+		if err != nil {
+			t.Fatalf("CursorPages: %v", err)
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		cursors = append(cursors, page.Next)
+	}
+
+	if len(ids) != 3 || ids[2] != "c" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if cursors[0] == "" || cursors[1] != "" {
+		t.Fatalf("unexpected cursors: %q", cursors)
+	}
+	if filters[0] != "status='ok'" {
+		t.Fatalf("unexpected first filter: %s", filters[0])
+	}
+	want := "(status='ok' && (created>'2024-01-02 00:00:00.000Z' || (created='2024-01-02 00:00:00.000Z' && id>'b')))"
+	if filters[1] != want {
+		t.Fatalf("unexpected keyset filter:\n got %s\nwant %s", filters[1], want)
+	}
+}
+
+func TestRepositoryListCursorRejectsMismatchedCursor(t *testing.T) {
+	cursor, err := encodeCursor(json.RawMessage(`{"id":"a","created":"x"}`), "created", false)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+
+	repo := NewRepository[cursorRecord](nil, "test")
+	_, err = repo.ListCursor(context.Background(), CursorOptions{SortField: "updated", After: cursor})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	_, err = repo.ListCursor(context.Background(), CursorOptions{After: "not a cursor!"})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for garbage, got %v", err)
+	}
+}
+
+func TestKeysetFilterDescendingNumber(t *testing.T) {
+	got, err := keysetFilter(cursorState{Field: "score", Descending: true, Value: []byte("42"), ID: []byte(`"x"`)})
+	if err != nil {
+		t.Fatalf("keysetFilter: %v", err)
+	}
+	if want := "(score<42 || (score=42 && id<'x'))"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// pbTime decodes PocketBase datetimes but encodes as time.Time does, in RFC 3339.
+type pbTime struct {
+	time.Time
+}
+
+func (t *pbTime) UnmarshalJSON(data []byte) error {
+	var dt DateTime
+	err := dt.UnmarshalJSON(data)
+	t.Time = dt.Time
+	return err
+}
+
+func TestRepositoryCursorUsesServerValues(t *testing.T) {
+	type timedRecord struct {
+		ID      string `json:"id"`
+		Created pbTime `json:"created"`
+	}
+
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("filter"))
+		items := []map[string]any{{"id": "a", "created": "2024-01-02 00:00:00.000Z"}}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 1})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[timedRecord](newTestClient(t, server), "test")
+	page, err := repo.ListCursor(context.Background(), CursorOptions{PerPage: 1})
+	if err != nil {
+		t.Fatalf("ListCursor: %v", err)
+	}
+	if page.Items[0].Created.Day() != 2 {
+		t.Fatalf("unexpected record: %+v", page.Items[0])
+	}
+	if _, err := repo.ListCursor(context.Background(), CursorOptions{PerPage: 1, After: page.Next}); err != nil {
+		t.Fatalf("ListCursor: %v", err)
+	}
+
+	want := "(created>'2024-01-02 00:00:00.000Z' || (created='2024-01-02 00:00:00.000Z' && id>'a'))"
+	if filters[1] != want {
+		t.Fatalf("unexpected keyset filter:\n got %s\nwant %s", filters[1], want)
+	}
+}
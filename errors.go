@@ -46,8 +46,56 @@ type pbField struct {
 }
 
 type pbError struct {
+	Message     string
+	Fields      []string
+	FieldErrors map[string]FieldError
+}
+
+// FieldError describes why PocketBase rejected a single field.
+type FieldError struct {
+	Code    string
+	Message string
+}
+
+// ValidationError reports per-field validation failures, as returned by
+// PocketBase for 400 and 422 responses or produced locally by
+// WithSchemaValidation. It matches ErrBadRequest or ErrValidation, depending
+// on Status, with errors.Is.
+type ValidationError struct {
+	Status  int
 	Message string
-	Fields  []string
+	Fields  map[string]FieldError
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	details := make([]string, 0, len(names))
+	for _, name := range names {
+		field := e.Fields[name]
+		if field.Message != "" {
+			details = append(details, fmt.Sprintf("%s: %s", name, field.Message))
+		} else {
+			details = append(details, fmt.Sprintf("%s: %s", name, field.Code))
+		}
+	}
+
+	msg := strings.Join(details, "; ")
+	if e.Message != "" {
+		msg = e.Message + ": " + msg
+	}
+	return wrapIfMessage(e.Unwrap(), msg).Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	if e.Status == 422 {
+		return ErrValidation
+	}
+	return ErrBadRequest
 }
 
 // mapHTTPError maps an HTTP status and optional body to meaningful errors.
@@ -64,6 +112,10 @@ func mapHTTPError(status int, body []byte) error {
 		msg = msg + ": " + strings.Join(errInfo.Fields, "; ")
 	}
 
+	if (status == 400 || status == 422) && len(errInfo.FieldErrors) > 0 {
+		return &ValidationError{Status: status, Message: errInfo.Message, Fields: errInfo.FieldErrors}
+	}
+
 	switch status {
 	case 400:
 		return wrapIfMessage(ErrBadRequest, msg)
@@ -110,6 +162,7 @@ func parsePBError(body []byte) pbError {
 	}
 
 	var fields []string
+	fieldErrors := make(map[string]FieldError)
 	if len(pbErr.Data) > 0 {
 		fieldNames := make([]string, 0, len(pbErr.Data))
 		for field := range pbErr.Data {
@@ -118,6 +171,9 @@ func parsePBError(body []byte) pbError {
 		sort.Strings(fieldNames)
 		for _, field := range fieldNames {
 			detail := pbErr.Data[field]
+			if detail.Code != "" || strings.TrimSpace(detail.Message) != "" {
+				fieldErrors[field] = FieldError{Code: detail.Code, Message: strings.TrimSpace(detail.Message)}
+			}
 			if msg := strings.TrimSpace(detail.Message); msg != "" {
 				fields = append(fields, fmt.Sprintf("%s: %s", field, msg))
 			} else if detail.Code != "" {
@@ -127,7 +183,8 @@ func parsePBError(body []byte) pbError {
 	}
 
 	return pbError{
-		Message: strings.TrimSpace(pbErr.Message),
-		Fields:  fields,
+		Message:     strings.TrimSpace(pbErr.Message),
+		Fields:      fields,
+		FieldErrors: fieldErrors,
 	}
 }
@@ -74,3 +74,22 @@ func TestMapHTTPErrorPassThrough(t *testing.T) {
 		t.Fatalf("expected body in error message, got %q", err.Error())
 	}
 }
+
+func TestMapHTTPErrorValidationFields(t *testing.T) {
+	body := `{"status":400,"message":"Failed to create record.","data":{"title":{"code":"validation_required","message":"Cannot be blank."}}}`
+	err := mapHTTPError(400, []byte(body))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %T", err)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+	if validationErr.Fields["title"].Code != "validation_required" {
+		t.Fatalf("unexpected fields: %+v", validationErr.Fields)
+	}
+	if got := err.Error(); got != "bad request: Failed to create record.: title: Cannot be blank." {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
@@ -0,0 +1,49 @@
+package pbclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Expanded decodes a record together with its "expand" object.
+// Use it as the repository type to load relations in one round trip:
+//
+//	type PostExpand struct {
+//		Author   *Expanded[User, UserExpand] `json:"author"`
+//		Comments []Comment                   `json:"comments_via_post"`
+//	}
+//	repo := NewRepository[Expanded[Post, PostExpand]](client, "posts")
+//	post, err := repo.Get(ctx, id, GetOptions{Expand: []string{"author.org", "comments_via_post"}})
+//
+// Nested expands are decoded by nesting Expanded in E. When marshalled, only
+// Record is encoded, so Expanded values can be passed back to Create and Update.
+type Expanded[T, E any] struct {
+	Record T
+	Expand E
+}
+
+// UnmarshalJSON decodes the record fields into Record and the "expand" object into Expand.
+func (e *Expanded[T, E]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.Record); err != nil {
+		return err
+	}
+
+	var aux struct {
+		Expand json.RawMessage `json:"expand"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Expand) == 0 || string(aux.Expand) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Expand, &e.Expand); err != nil {
+		return fmt.Errorf("decode expand: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON encodes only the record; expanded relations are read-only.
+func (e Expanded[T, E]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Record)
+}
@@ -0,0 +1,98 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type expandOrg struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type expandUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type expandUserRelations struct {
+	Org *expandOrg `json:"org"`
+}
+
+type expandPost struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+type expandPostRelations struct {
+	Author   *Expanded[expandUser, expandUserRelations] `json:"author"`
+	Comments []testRecord                               `json:"comments_via_post"`
+}
+
+func TestRepositoryGetWithExpand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("expand"); got != "author.org,comments_via_post" {
+			t.Fatalf("unexpected expand: %q", got)
+		}
+		_, _ = w.Write([]byte(`{
+			"id":"p1","title":"hello","author":"u1",
+			"expand":{
+				"author":{"id":"u1","name":"ann","expand":{"org":{"id":"o1","name":"acme"}}},
+				"comments_via_post":[{"id":"c1","name":"first"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[Expanded[expandPost, expandPostRelations]](newTestClient(t, server), "posts")
+
+	got, err := repo.Get(context.Background(), "p1", GetOptions{Expand: []string{"author.org", "comments_via_post"}})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Record.Title != "hello" || got.Record.Author != "u1" {
+		t.Fatalf("unexpected record: %+v", got.Record)
+	}
+	if got.Expand.Author == nil || got.Expand.Author.Record.Name != "ann" {
+		t.Fatalf("author not expanded: %+v", got.Expand.Author)
+	}
+	if got.Expand.Author.Expand.Org == nil || got.Expand.Author.Expand.Org.Name != "acme" {
+		t.Fatalf("nested org not expanded: %+v", got.Expand.Author.Expand)
+	}
+	if len(got.Expand.Comments) != 1 || got.Expand.Comments[0].ID != "c1" {
+		t.Fatalf("back-relation not expanded: %+v", got.Expand.Comments)
+	}
+}
+
+func TestRepositoryListSendsExpand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("expand"); got != "author" {
+			t.Fatalf("unexpected expand: %q", got)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": []any{}, "page": 1, "perPage": 30})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "posts")
+	if _, err := repo.List(context.Background(), ListOptions{Expand: []string{"author"}}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+}
+
+func TestExpandedMarshalsRecordOnly(t *testing.T) {
+	value := Expanded[expandPost, expandPostRelations]{
+		Record: expandPost{ID: "p1", Title: "hello"},
+		Expand: expandPostRelations{Comments: []testRecord{{ID: "c1"}}},
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"id":"p1","title":"hello","author":""}` {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,330 @@
+package pbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// recordWrapper is implemented by types such as Expanded that wrap the actual record.
+type recordWrapper interface {
+	unwrapRecord() any
+}
+
+func (e Expanded[T, E]) unwrapRecord() any {
+	return e.Record
+}
+
+// expandedRecord is implemented by Expanded to expose its record and expand types.
+type expandedRecord interface {
+	expandedTypes() (record, expand reflect.Type)
+}
+
+func (Expanded[T, E]) expandedTypes() (reflect.Type, reflect.Type) {
+	return reflect.TypeFor[T](), reflect.TypeFor[E]()
+}
+
+// maxProjectionDepth bounds recursion through nested expands; PocketBase
+// expands at most six levels deep.
+const maxProjectionDepth = 6
+
+// projectionFields derives the fields query parameter from the json tags of t,
+// promoting embedded structs and descending into the expand type of Expanded
+// as "expand.<relation>.<field>". It returns nil when t is not a struct, in
+// which case every field is requested.
+func projectionFields(t reflect.Type) []string {
+	fields, ok := appendProjection(nil, "", t, 0)
+	if !ok {
+		return nil
+	}
+	return fields
+}
+
+func appendProjection(out []string, prefix string, t reflect.Type, depth int) ([]string, bool) {
+	t = elemType(t)
+	if depth > maxProjectionDepth {
+		return out, false
+	}
+
+	if t.Implements(reflect.TypeFor[expandedRecord]()) {
+		recordType, expandType := reflect.Zero(t).Interface().(expandedRecord).expandedTypes()
+		out, ok := appendProjection(out, prefix, recordType, depth)
+		if !ok {
+			return out, false
+		}
+		expandType = elemType(expandType)
+		if expandType.Kind() != reflect.Struct {
+			return append(out, prefix+"expand"), true
+		}
+		for _, rel := range structFieldTypes(expandType) {
+			var relOK bool
+			relPrefix := prefix + "expand." + rel.name
+			if out, relOK = appendProjection(out, relPrefix+".", rel.typ, depth+1); !relOK {
+				out = append(out, relPrefix)
+			}
+		}
+		return out, true
+	}
+
+	if t.Kind() != reflect.Struct {
+		return out, false
+	}
+	for _, field := range structFieldTypes(t) {
+		out = append(out, prefix+field.name)
+	}
+	return out, true
+}
+
+type namedType struct {
+	name string
+	typ  reflect.Type
+}
+
+// structFieldTypes lists the JSON-named fields of t, promoting untagged embedded structs.
+func structFieldTypes(t reflect.Type) []namedType {
+	fields := jsonFields(t)
+	out := make([]namedType, len(fields))
+	for i, f := range fields {
+		out[i] = namedType{name: f.name, typ: f.typ}
+	}
+	return out
+}
+
+type jsonField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	tagged bool
+}
+
+// jsonFields resolves the JSON-named fields of the struct type t the way
+// encoding/json does: fields promoted from untagged embedded structs are
+// visited breadth first, the shallowest field with a given name wins, and
+// among fields at the same depth a single tagged one wins, otherwise the name
+// is ambiguous and dropped. Fields are returned in struct index order.
+func jsonFields(t reflect.Type) []jsonField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var out []jsonField
+	resolved := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		var names []string
+		candidates := make(map[string][]jsonField)
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				name, ok := jsonFieldName(sf)
+				if !ok {
+					continue
+				}
+				index := append(append([]int(nil), e.index...), i)
+				if sf.Anonymous && name == "" {
+					st := sf.Type
+					if st.Kind() == reflect.Pointer {
+						st = st.Elem()
+					}
+					next = append(next, embedded{typ: st, index: index})
+					continue
+				}
+				if resolved[name] {
+					continue
+				}
+				if _, ok := candidates[name]; !ok {
+					names = append(names, name)
+				}
+				tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+				candidates[name] = append(candidates[name], jsonField{name: name, index: index, typ: sf.Type, tagged: tag != ""})
+			}
+		}
+
+		for _, name := range names {
+			resolved[name] = true
+			if field, ok := dominantField(candidates[name]); ok {
+				out = append(out, field)
+			}
+		}
+	}
+
+	slices.SortFunc(out, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return out
+}
+
+// dominantField picks the field that wins among same-depth candidates sharing
+// a JSON name, reporting false when the name is ambiguous.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	if len(fields) == 1 {
+		return fields[0], true
+	}
+	var winner jsonField
+	tagged := 0
+	for _, f := range fields {
+		if f.tagged {
+			winner = f
+			tagged++
+		}
+	}
+	return winner, tagged == 1
+}
+
+// elemType strips pointers, slices and arrays from t.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// jsonFieldValues returns the JSON-named top-level fields of record. Unlike a
+// JSON round trip, struct fields tagged omitempty are kept even when they hold
+// zero values, so callers can deliberately send false, 0 or "".
+func jsonFieldValues(record any) (map[string]any, error) {
+	if w, ok := record.(recordWrapper); ok {
+		return jsonFieldValues(w.unwrapRecord())
+	}
+
+	v := reflect.ValueOf(record)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, fmt.Errorf("record is nil")
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		fields, err := recordFields(record)
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(fields))
+		for name, raw := range fields {
+			out[name] = raw
+		}
+		return out, nil
+	}
+
+	out := make(map[string]any)
+	collectStructValues(v, out)
+	return out, nil
+}
+
+func collectStructValues(v reflect.Value, out map[string]any) {
+	for _, field := range jsonFields(v.Type()) {
+		if fv, ok := fieldByIndex(v, field.index); ok {
+			out[field.name] = fv.Interface()
+		}
+	}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports false instead of
+// panicking when an embedded struct pointer on the path is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// jsonFieldName returns the JSON name of a struct field. An empty name with ok
+// set means an untagged embedded struct whose fields are promoted.
+func jsonFieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+
+	if sf.Anonymous && name == "" {
+		t := sf.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			return "", true
+		}
+	}
+	if !sf.IsExported() {
+		return "", false
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
+
+// systemFields are managed by PocketBase and never sent in diffs.
+var systemFields = map[string]bool{
+	"id":             true,
+	"created":        true,
+	"updated":        true,
+	"collectionId":   true,
+	"collectionName": true,
+	"expand":         true,
+}
+
+// changedFields returns the fields of after whose JSON encoding differs from before.
+// Fields missing from after are reported as null.
+func changedFields(before, after any) (map[string]any, error) {
+	oldValues, err := jsonFieldValues(before)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := jsonFieldValues(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]any)
+	for name, value := range newValues {
+		if systemFields[name] {
+			continue
+		}
+		equal, err := jsonEqual(oldValues[name], value)
+		if err != nil {
+			return nil, err
+		}
+		if _, existed := oldValues[name]; !existed || !equal {
+			changes[name] = value
+		}
+	}
+	for name := range oldValues {
+		if _, ok := newValues[name]; !ok && !systemFields[name] {
+			changes[name] = nil
+		}
+	}
+	return changes, nil
+}
+
+func jsonEqual(a, b any) (bool, error) {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false, fmt.Errorf("marshal field: %w", err)
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false, fmt.Errorf("marshal field: %w", err)
+	}
+	return string(left) == string(right), nil
+}
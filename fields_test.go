@@ -0,0 +1,156 @@
+package pbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type projectionUser struct {
+	BaseRecord
+	Name     string `json:"name"`
+	Internal string `json:"-"`
+	secret   string
+}
+
+type projectionUserExpand struct {
+	Org *struct {
+		Title string `json:"title"`
+	} `json:"org"`
+}
+
+type projectionPostExpand struct {
+	Author   *Expanded[projectionUser, projectionUserExpand] `json:"author"`
+	Comments []struct {
+		Body string `json:"body"`
+	} `json:"comments_via_post"`
+	Meta map[string]any `json:"meta"`
+}
+
+type projectionPost struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+func TestProjectionFields(t *testing.T) {
+	got := projectionFields(reflect.TypeFor[projectionUser]())
+	want := []string{"id", "collectionId", "collectionName", "created", "updated", "name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+
+	got = projectionFields(reflect.TypeFor[Expanded[projectionPost, projectionPostExpand]]())
+	want = []string{
+		"id", "title",
+		"expand.author.id", "expand.author.collectionId", "expand.author.collectionName",
+		"expand.author.created", "expand.author.updated", "expand.author.name",
+		"expand.author.expand.org.title",
+		"expand.comments_via_post.body",
+		"expand.meta",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+
+	if got := projectionFields(reflect.TypeFor[map[string]any]()); got != nil {
+		t.Fatalf("expected no projection for maps, got %v", got)
+	}
+}
+
+func TestRepositoryAutoFields(t *testing.T) {
+	var fields []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		if strings.HasSuffix(r.URL.Path, "/records") {
+			writeJSON(w, http.StatusOK, map[string]any{"items": []any{}, "page": 1, "perPage": 30})
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"abc"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[projectionPost](newTestClient(t, server), "posts", WithAutoFields())
+	ctx := context.Background()
+
+	if _, err := repo.List(ctx, ListOptions{}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := repo.List(ctx, ListOptions{Fields: []string{"id"}}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := repo.List(ctx, ListOptions{AllFields: true}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := repo.Get(ctx, "abc"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := repo.Get(ctx, "abc", GetOptions{AllFields: true}); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	want := []string{"id,title", "id", "", "id,title", ""}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got fields %q, want %q", fields, want)
+	}
+}
+
+type shadowInner struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type shadowOuter struct {
+	shadowInner
+	Title string `json:"title"`
+}
+
+type ambiguousA struct {
+	Name string
+}
+
+type ambiguousB struct {
+	Name string
+}
+
+type ambiguousOuter struct {
+	ambiguousA
+	ambiguousB
+	ID string `json:"id"`
+}
+
+func TestJSONFieldsShadowing(t *testing.T) {
+	record := shadowOuter{shadowInner: shadowInner{Title: "inner", Body: "b"}, Title: "outer"}
+	values, err := jsonFieldValues(record)
+	if err != nil {
+		t.Fatalf("jsonFieldValues: %v", err)
+	}
+	if want := map[string]any{"title": "outer", "body": "b"}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %v, want %v", values, want)
+	}
+
+	after := record
+	after.Title = "changed"
+	changes, err := changedFields(record, after)
+	if err != nil {
+		t.Fatalf("changedFields: %v", err)
+	}
+	if want := map[string]any{"title": "changed"}; !reflect.DeepEqual(changes, want) {
+		t.Fatalf("got %v, want %v", changes, want)
+	}
+
+	if got, want := projectionFields(reflect.TypeFor[shadowOuter]()), []string{"body", "title"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Same-depth fields with the same name are ambiguous and dropped, as in encoding/json.
+	values, err = jsonFieldValues(ambiguousOuter{ambiguousA{"a"}, ambiguousB{"b"}, "x"})
+	if err != nil {
+		t.Fatalf("jsonFieldValues: %v", err)
+	}
+	if want := map[string]any{"id": "x"}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %v, want %v", values, want)
+	}
+}
@@ -0,0 +1,308 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// File is a file to upload into a file field.
+type File struct {
+	Name        string
+	Reader      io.Reader
+	ContentType string
+}
+
+// FileUpload describes changes to file fields sent along with a create or update.
+type FileUpload struct {
+	ops []fileOp
+}
+
+type fileOp struct {
+	key    string
+	field  string
+	files  []File
+	remove []string
+}
+
+// NewFileUpload returns an empty set of file field changes.
+func NewFileUpload() *FileUpload {
+	return &FileUpload{}
+}
+
+// Set replaces the contents of a file field with files.
+func (u *FileUpload) Set(field string, files ...File) *FileUpload {
+	u.ops = append(u.ops, fileOp{key: field, field: field, files: files})
+	return u
+}
+
+// Append adds files to a multi-file field, keeping the existing ones.
+func (u *FileUpload) Append(field string, files ...File) *FileUpload {
+	u.ops = append(u.ops, fileOp{key: field + "+", field: field, files: files})
+	return u
+}
+
+// Remove deletes the named files from a file field.
+func (u *FileUpload) Remove(field string, filenames ...string) *FileUpload {
+	u.ops = append(u.ops, fileOp{key: field + "-", field: field, remove: filenames})
+	return u
+}
+
+// CreateWithFiles inserts a new record together with uploaded files.
+func (r *Repository[T]) CreateWithFiles(ctx context.Context, record T, files *FileUpload) (*T, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if r.collection == "" {
+		return nil, errors.New("collection is required")
+	}
+
+	path := fmt.Sprintf("/api/collections/%s/records", url.PathEscape(r.collection))
+	return r.hookedCreate(ctx, record, func(record T) (*T, error) {
+		return r.sendMultipart(ctx, http.MethodPost, path, record, files)
+	})
+}
+
+// UpdateWithFiles patches a record and applies the file field changes.
+func (r *Repository[T]) UpdateWithFiles(ctx context.Context, id string, record T, files *FileUpload) (*T, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if r.collection == "" {
+		return nil, errors.New("collection is required")
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id is required")
+	}
+
+	path := fmt.Sprintf("/api/collections/%s/records/%s", url.PathEscape(r.collection), url.PathEscape(id))
+	return r.hookedUpdate(ctx, id, record, func(record T) (*T, error) {
+		return r.sendMultipart(ctx, http.MethodPatch, path, record, files)
+	})
+}
+
+// sendMultipart encodes record as the @jsonPayload form field followed by the
+// file parts. The body is streamed while it is encoded, so the client has to
+// implement HeaderDoer and the request is not retried.
+func (r *Repository[T]) sendMultipart(ctx context.Context, method, path string, record T, files *FileUpload) (*T, error) {
+	doer, ok := r.client.(HeaderDoer)
+	if !ok {
+		return nil, errors.New("file uploads require a client that implements HeaderDoer")
+	}
+
+	fields, err := recordFields(record)
+	if err != nil {
+		return nil, err
+	}
+	if files != nil {
+		// File fields are driven by the upload; drop their JSON values so they do not conflict.
+		for _, op := range files.ops {
+			delete(fields, op.field)
+		}
+	}
+
+	if err := r.validatePayload(ctx, fields, method == http.MethodPost); err != nil {
+		return nil, err
+	}
+
+	jsonPayload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+
+	body, writer := io.Pipe()
+	defer body.Close()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeMultipart(form, jsonPayload, files))
+	}()
+
+	header := http.Header{"Content-Type": {form.FormDataContentType()}}
+	resp, err := doer.DoWithHeader(ctx, method, path, header, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out T
+	if err := decodeJSONResponse(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// writeMultipart writes the form parts of a record upload and closes the form.
+func writeMultipart(form *multipart.Writer, jsonPayload []byte, files *FileUpload) error {
+	if err := form.WriteField("@jsonPayload", string(jsonPayload)); err != nil {
+		return fmt.Errorf("write json payload: %w", err)
+	}
+	if files != nil {
+		for _, op := range files.ops {
+			if err := writeFileOp(form, op); err != nil {
+				return err
+			}
+		}
+	}
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("close multipart body: %w", err)
+	}
+	return nil
+}
+
+func writeFileOp(writer *multipart.Writer, op fileOp) error {
+	if len(op.remove) > 0 {
+		for _, name := range op.remove {
+			if err := writer.WriteField(op.key, name); err != nil {
+				return fmt.Errorf("write %s: %w", op.key, err)
+			}
+		}
+		return nil
+	}
+
+	if len(op.files) == 0 {
+		// Setting a file field to nothing clears it.
+		if err := writer.WriteField(op.key, ""); err != nil {
+			return fmt.Errorf("write %s: %w", op.key, err)
+		}
+		return nil
+	}
+
+	for _, file := range op.files {
+		if file.Reader == nil {
+			return fmt.Errorf("file %q for %s has no reader", file.Name, op.field)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(op.key), escapeQuotes(file.Name)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("create part for %s: %w", op.field, err)
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return fmt.Errorf("copy file %q: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// FileURLOptions configures file URLs and downloads.
+type FileURLOptions struct {
+	// Thumb requests an image thumbnail, e.g. "100x100", "0x50" or "100x100t".
+	Thumb string
+	// Token grants access to protected files; see FileToken.
+	Token string
+	// Download forces a Content-Disposition: attachment response.
+	Download bool
+}
+
+// FilePath returns the API path of a stored file, relative to the PocketBase base URL.
+func FilePath(collection, recordID, filename string, opts FileURLOptions) string {
+	path := fmt.Sprintf("/api/files/%s/%s/%s",
+		url.PathEscape(strings.TrimSpace(collection)),
+		url.PathEscape(recordID),
+		url.PathEscape(filename),
+	)
+
+	params := url.Values{}
+	if opts.Thumb != "" {
+		params.Set("thumb", opts.Thumb)
+	}
+	if opts.Token != "" {
+		params.Set("token", opts.Token)
+	}
+	if opts.Download {
+		params.Set("download", "1")
+	}
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	return path
+}
+
+// FileURL returns the absolute URL of a stored file on the PocketBase instance at baseURL.
+func FileURL(baseURL, collection, recordID, filename string, opts FileURLOptions) string {
+	return strings.TrimRight(strings.TrimSpace(baseURL), "/") + FilePath(collection, recordID, filename, opts)
+}
+
+// FilePath returns the API path of a file stored on a record of the repository's collection.
+func (r *Repository[T]) FilePath(recordID, filename string, opts FileURLOptions) string {
+	return FilePath(r.collection, recordID, filename, opts)
+}
+
+// FileToken requests a short-lived token for accessing protected files.
+func FileToken(ctx context.Context, client AuthenticatedClient) (string, error) {
+	if client == nil {
+		return "", errors.New("client is nil")
+	}
+
+	resp, err := client.Do(ctx, http.MethodPost, "/api/files/token", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Token string `json:"token"`
+	}
+	if err := decodeJSONResponse(resp, &payload); err != nil {
+		return "", err
+	}
+	if payload.Token == "" {
+		return "", errors.New("file token missing in response")
+	}
+	return payload.Token, nil
+}
+
+// DownloadFile streams a stored file. The caller must close the returned reader.
+// With the built-in client the download is not cut off by the client's HTTP
+// timeout; cancel ctx to abort it. Other clients fall back to Do.
+func (r *Repository[T]) DownloadFile(ctx context.Context, recordID, filename string, opts FileURLOptions) (io.ReadCloser, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if strings.TrimSpace(recordID) == "" {
+		return nil, errors.New("record id is required")
+	}
+	if strings.TrimSpace(filename) == "" {
+		return nil, errors.New("filename is required")
+	}
+
+	var resp *http.Response
+	var err error
+	if s, ok := r.client.(streamer); ok {
+		resp, err = s.stream(ctx, r.FilePath(recordID, filename, opts), nil)
+	} else {
+		resp, err = r.client.Do(ctx, http.MethodGet, r.FilePath(recordID, filename, opts), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("download failed: status %d: %w", resp.StatusCode, readErr)
+		}
+		return nil, mapHTTPError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,193 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepositoryUpdateWithFilesSendsMultipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/collections/docs/records/d1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		form := r.MultipartForm
+
+		if got := form.Value["@jsonPayload"]; len(got) != 1 || got[0] != `{"id":"","name":"report"}` {
+			t.Fatalf("unexpected json payload: %v", got)
+		}
+		if got := form.Value["attachments-"]; len(got) != 2 || got[0] != "old_1.pdf" {
+			t.Fatalf("unexpected removals: %v", got)
+		}
+
+		appended := form.File["attachments+"]
+		if len(appended) != 1 || appended[0].Filename != "new.txt" || appended[0].Header.Get("Content-Type") != "text/plain" {
+			t.Fatalf("unexpected appended files: %+v", appended)
+		}
+		f, _ := appended[0].Open()
+		data, _ := io.ReadAll(f)
+		if string(data) != "hello" {
+			t.Fatalf("unexpected file content: %q", data)
+		}
+
+		if cover := form.File["cover"]; len(cover) != 1 || cover[0].Header.Get("Content-Type") != "application/octet-stream" {
+			t.Fatalf("unexpected cover: %+v", cover)
+		}
+		_, _ = w.Write([]byte(`{"id":"d1","name":"report"}`))
+	}))
+	defer server.Close()
+
+	type doc struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Cover string `json:"cover"`
+	}
+	repo := NewRepository[doc](newTestClient(t, server), "docs")
+
+	files := NewFileUpload().
+		Append("attachments", File{Name: "new.txt", Reader: strings.NewReader("hello"), ContentType: "text/plain"}).
+		Remove("attachments", "old_1.pdf", "old_2.pdf").
+		Set("cover", File{Name: "cover.bin", Reader: strings.NewReader("\x00\x01")})
+
+	got, err := repo.UpdateWithFiles(context.Background(), "d1", doc{Name: "report", Cover: "stale.png"}, files)
+	if err != nil {
+		t.Fatalf("UpdateWithFiles: %v", err)
+	}
+	if got.ID != "d1" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+}
+
+func TestFileURLBuilders(t *testing.T) {
+	got := FileURL("https://pb.example.com/", "posts", "abc", "photo 1.png", FileURLOptions{Thumb: "100x100", Token: "tok"})
+	want := "https://pb.example.com/api/files/posts/abc/photo%201.png?thumb=100x100&token=tok"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if got := FilePath("posts", "abc", "a.pdf", FileURLOptions{Download: true}); got != "/api/files/posts/abc/a.pdf?download=1" {
+		t.Fatalf("unexpected path %q", got)
+	}
+}
+
+func TestFileTokenAndDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/files/token":
+			_, _ = w.Write([]byte(`{"token":"file-token"}`))
+		case "/api/files/docs/d1/secret.txt":
+			if r.URL.Query().Get("token") != "file-token" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("top secret"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	repo := NewRepository[testRecord](client, "docs")
+	ctx := context.Background()
+
+	if _, err := repo.DownloadFile(ctx, "d1", "secret.txt", FileURLOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound without token, got %v", err)
+	}
+
+	token, err := FileToken(ctx, client)
+	if err != nil {
+		t.Fatalf("FileToken: %v", err)
+	}
+	body, err := repo.DownloadFile(ctx, "d1", "secret.txt", FileURLOptions{Token: token})
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "top secret" {
+		t.Fatalf("unexpected content %q", data)
+	}
+}
+
+func TestDownloadFileOutlivesClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/files/docs/d1/big.bin" {
+			writeJSON(w, http.StatusNotFound, map[string]any{"message": "missing"})
+			return
+		}
+		_, _ = w.Write([]byte("first,"))
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte("second"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	client.(*authenticatedClient).client.httpClient = &http.Client{
+		Transport: server.Client().Transport,
+		Timeout:   50 * time.Millisecond,
+	}
+	repo := NewRepository[testRecord](client, "docs")
+
+	body, err := repo.DownloadFile(context.Background(), "d1", "big.bin", FileURLOptions{})
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil || string(data) != "first,second" {
+		t.Fatalf("unexpected content %q, %v", data, err)
+	}
+
+	if _, err := repo.DownloadFile(context.Background(), "d1", "other.bin", FileURLOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// headerRecordingClient wraps a client and records the headers of DoWithHeader calls.
+type headerRecordingClient struct {
+	AuthenticatedClient
+	headers []http.Header
+}
+
+func (c *headerRecordingClient) DoWithHeader(ctx context.Context, method, path string, header http.Header, body io.Reader) (*http.Response, error) {
+	c.headers = append(c.headers, header)
+	return c.AuthenticatedClient.(HeaderDoer).DoWithHeader(ctx, method, path, header, body)
+}
+
+func TestRepositoryCreateWithFilesPassesContentTypeExplicitly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id":"d1"}`))
+	}))
+	defer server.Close()
+
+	wrapped := &headerRecordingClient{AuthenticatedClient: newTestClient(t, server)}
+	repo := NewRepository[testRecord](wrapped, "docs")
+	files := NewFileUpload().Set("cover", File{Name: "a.txt", Reader: strings.NewReader("a")})
+
+	if _, err := repo.CreateWithFiles(context.Background(), testRecord{Name: "x"}, files); err != nil {
+		t.Fatalf("CreateWithFiles: %v", err)
+	}
+	if len(wrapped.headers) != 1 || !strings.HasPrefix(wrapped.headers[0].Get("Content-Type"), "multipart/form-data; boundary=") {
+		t.Fatalf("unexpected headers: %v", wrapped.headers)
+	}
+
+	// A wrapper that only forwards Do cannot send the multipart Content-Type.
+	plain := struct{ AuthenticatedClient }{newTestClient(t, server)}
+	repo = NewRepository[testRecord](plain, "docs")
+	if _, err := repo.CreateWithFiles(context.Background(), testRecord{Name: "x"}, files); err == nil {
+		t.Fatalf("expected an error for a client without HeaderDoer")
+	}
+}
@@ -0,0 +1,88 @@
+package pbclient
+
+import (
+	"context"
+	"sync"
+)
+
+// GetFullList loads every record matching opts. It fetches the first page to
+// learn TotalPages and then fetches the remaining pages with up to concurrency
+// parallel requests. Items are returned in page order, and the first error
+// cancels any pages still in flight. Requests go through the client's Do, so
+// they share its retry and rate-limit handling.
+func (r *Repository[T]) GetFullList(ctx context.Context, opts ListOptions, concurrency int) ([]T, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if opts.PerPage <= 0 {
+		opts.PerPage = defaultIterPerPage
+	}
+	opts.Page = 1
+	opts.SkipTotal = false
+
+	first, err := r.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if first.TotalPages <= 1 {
+		return first.Items, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]T, first.TotalPages)
+	pages[0] = first.Items
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	jobs := make(chan int)
+
+	workers := min(concurrency, first.TotalPages-1)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				pageOpts := opts
+				pageOpts.Page = page
+				res, err := r.List(ctx, pageOpts)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[page-1] = res.Items
+			}
+		}()
+	}
+
+feed:
+	for page := 2; page <= first.TotalPages; page++ {
+		select {
+		case jobs <- page:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	all := make([]T, 0, first.TotalItems)
+	for _, items := range pages {
+		all = append(all, items...)
+	}
+	return all, nil
+}
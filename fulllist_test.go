@@ -0,0 +1,78 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRepositoryGetFullListKeepsOrder(t *testing.T) {
+	const total = 23
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		q := r.URL.Query()
+		page := parseIntDefault(q.Get("page"), 1)
+		perPage := parseIntDefault(q.Get("perPage"), 30)
+
+		items := make([]testRecord, 0, perPage)
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, testRecord{ID: strconv.Itoa(i)})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items":      items,
+			"page":       page,
+			"perPage":    perPage,
+			"totalItems": total,
+			"totalPages": (total + perPage - 1) / perPage,
+		})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, err := repo.GetFullList(context.Background(), ListOptions{PerPage: 5}, 3)
+	if err != nil {
+		t.Fatalf("GetFullList: %v", err)
+	}
+	if len(items) != total {
+		t.Fatalf("expected %d items, got %d", total, len(items))
+	}
+	for i, item := range items {
+		if item.ID != strconv.Itoa(i) {
+			t.Fatalf("item %d out of order: %s", i, item.ID)
+		}
+	}
+	if got := requests.Load(); got != 5 {
+		t.Fatalf("expected 5 requests, got %d", got)
+	}
+}
+
+func TestRepositoryGetFullListReturnsFirstError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := parseIntDefault(r.URL.Query().Get("page"), 1)
+		if page == 3 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items":      []testRecord{{ID: strconv.Itoa(page)}},
+			"page":       page,
+			"perPage":    1,
+			"totalItems": 10,
+			"totalPages": 10,
+		})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	_, err := repo.GetFullList(context.Background(), ListOptions{PerPage: 1}, 2)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxIDFilterLength bounds the filter of one GetMany request so the URL stays
+	// well below common server and proxy limits once encoded.
+	maxIDFilterLength = 2000
+	maxIDsPerRequest  = 200
+
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// GetMany fetches the records with the given ids using as few list requests as
+// possible. Records are returned in input order; ids without a record are
+// reported in missing instead of failing the call. Only the first GetOptions
+// value's Fields and Expand are used.
+func (r *Repository[T]) GetMany(ctx context.Context, ids []string, opts ...GetOptions) (items []T, missing []string, err error) {
+	var opt GetOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	found, err := r.getMany(ctx, ids, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items = make([]T, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := found[id]; ok {
+			items = append(items, item)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return items, missing, nil
+}
+
+// getMany returns the records with ids, keyed by id.
+func (r *Repository[T]) getMany(ctx context.Context, ids []string, opt GetOptions) (map[string]T, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	// Items are decoded from raw JSON so the id is known even if T has no id field.
+	raw := NewRepository[json.RawMessage](r.client, r.collection)
+	fields := opt.Fields
+	if len(fields) == 0 && !opt.AllFields {
+		fields = r.autoFields
+	}
+	if len(fields) > 0 && !slices.Contains(fields, "id") && !slices.Contains(fields, "*") {
+		fields = append(slices.Clip(fields), "id")
+	}
+
+	match := idFilter(reflect.TypeFor[T]())
+	found := make(map[string]T, len(unique))
+	for _, chunk := range idFilterChunks(unique, match) {
+		res, err := raw.List(ctx, ListOptions{
+			Page:      1,
+			PerPage:   len(chunk),
+			Filter:    Or(mapSlice(chunk, match)...),
+			Fields:    fields,
+			Expand:    opt.Expand,
+			SkipTotal: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, data := range res.Items {
+			// View collections may expose non-string ids.
+			var key struct {
+				ID any `json:"id"`
+			}
+			if err := json.Unmarshal(data, &key); err != nil {
+				return nil, fmt.Errorf("decode record id: %w", err)
+			}
+			var item T
+			if err := json.Unmarshal(data, &item); err != nil {
+				return nil, fmt.Errorf("decode record: %w", err)
+			}
+			found[stringValue(key.ID)] = item
+		}
+	}
+	return found, nil
+}
+
+// idFilter returns the filter matching a single id. View collections may use
+// numeric id columns, which do not compare equal to quoted text, so when the
+// id field of t is numeric, ids that are numbers are matched unquoted.
+func idFilter(t reflect.Type) func(id string) string {
+	t = elemType(t)
+	if t.Implements(reflect.TypeFor[expandedRecord]()) {
+		t, _ = reflect.Zero(t).Interface().(expandedRecord).expandedTypes()
+		t = elemType(t)
+	}
+
+	numeric := false
+	if t.Kind() == reflect.Struct {
+		for _, field := range structFieldTypes(t) {
+			if field.name == "id" {
+				switch elemType(field.typ).Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+					reflect.Float32, reflect.Float64:
+					numeric = true
+				}
+			}
+		}
+	}
+
+	return func(id string) string {
+		if numeric {
+			if _, err := strconv.ParseFloat(id, 64); err == nil {
+				return "id=" + id
+			}
+		}
+		return Eq("id", id)
+	}
+}
+
+// idFilterChunks splits ids into groups whose id filter fits maxIDFilterLength.
+func idFilterChunks(ids []string, match func(id string) string) [][]string {
+	var chunks [][]string
+	var current []string
+	length := 0
+	for _, id := range ids {
+		size := len(match(id)) + len(" || ")
+		if len(current) > 0 && (length+size > maxIDFilterLength || len(current) == maxIDsPerRequest) {
+			chunks = append(chunks, current)
+			current, length = nil, 0
+		}
+		current = append(current, id)
+		length += size
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+func mapSlice[S, D any](values []S, fn func(S) D) []D {
+	out := make([]D, len(values))
+	for i, v := range values {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// LoaderOptions configures a Loader.
+type LoaderOptions struct {
+	// Wait is how long the loader collects ids before fetching them; defaults to 2ms.
+	Wait time.Duration
+	// MaxBatch dispatches a batch early once it holds this many ids; defaults to 100.
+	MaxBatch int
+	// Get is applied to every fetch.
+	Get GetOptions
+}
+
+// Loader coalesces concurrent Load calls made within a short window into one
+// GetMany-style request, avoiding N+1 lookups when resolving relations.
+// It does not cache results across batches.
+type Loader[T any] struct {
+	repo *Repository[T]
+	opts LoaderOptions
+
+	mu      sync.Mutex
+	pending *loaderBatch[T]
+}
+
+type loaderBatch[T any] struct {
+	ctx     context.Context
+	ids     []string
+	seen    map[string]bool
+	started bool
+	done    chan struct{}
+	found   map[string]T
+	err     error
+}
+
+// NewLoader returns a Loader backed by the repository.
+func (r *Repository[T]) NewLoader(opts LoaderOptions) *Loader[T] {
+	if opts.Wait <= 0 {
+		opts.Wait = defaultLoaderWait
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = defaultLoaderMaxBatch
+	}
+	return &Loader[T]{repo: r, opts: opts}
+}
+
+// Load returns the record with id, or ErrNotFound. The request runs with the
+// values, but not the cancellation, of the context that opened the batch.
+func (l *Loader[T]) Load(ctx context.Context, id string) (*T, error) {
+	l.mu.Lock()
+	batch := l.pending
+	if batch == nil {
+		batch = &loaderBatch[T]{
+			ctx:  context.WithoutCancel(ctx),
+			seen: make(map[string]bool),
+			done: make(chan struct{}),
+		}
+		l.pending = batch
+		time.AfterFunc(l.opts.Wait, func() { l.dispatch(batch) })
+	}
+	if !batch.seen[id] {
+		batch.seen[id] = true
+		batch.ids = append(batch.ids, id)
+	}
+	full := len(batch.ids) >= l.opts.MaxBatch
+	l.mu.Unlock()
+
+	if full {
+		go l.dispatch(batch)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-batch.done:
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	item, ok := batch.found[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &item, nil
+}
+
+// dispatch fetches batch once, whichever of the timer and the size limit comes first.
+func (l *Loader[T]) dispatch(batch *loaderBatch[T]) {
+	l.mu.Lock()
+	if batch.started {
+		l.mu.Unlock()
+		return
+	}
+	batch.started = true
+	if l.pending == batch {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	batch.found, batch.err = l.repo.getMany(batch.ctx, batch.ids, l.opts.Get)
+	close(batch.done)
+}
@@ -0,0 +1,145 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var idFilterPattern = regexp.MustCompile(`id='([^']+)'`)
+
+// newIDServer serves list requests filtered by id for the ids in existing.
+func newIDServer(t *testing.T, existing map[string]bool, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		items := make([]testRecord, 0)
+		for _, m := range idFilterPattern.FindAllStringSubmatch(r.URL.Query().Get("filter"), -1) {
+			if existing[m[1]] {
+				items = append(items, testRecord{ID: m[1], Name: "name-" + m[1]})
+			}
+		}
+		// Return in reverse to make sure results are reordered client side.
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": len(items)})
+	}))
+}
+
+func TestRepositoryGetManyKeepsInputOrder(t *testing.T) {
+	var requests atomic.Int32
+	server := newIDServer(t, map[string]bool{"a": true, "b": true, "c": true}, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, missing, err := repo.GetMany(context.Background(), []string{"c", "x", "a", "c"})
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.ID)
+	}
+	if strings.Join(got, ",") != "c,a,c" {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	if len(missing) != 1 || missing[0] != "x" {
+		t.Fatalf("unexpected missing ids: %v", missing)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestRepositoryGetManyChunksLongFilters(t *testing.T) {
+	existing := make(map[string]bool)
+	var ids []string
+	for i := 0; i < 300; i++ {
+		id := fmt.Sprintf("record%09d", i)
+		existing[id] = true
+		ids = append(ids, id)
+	}
+
+	var requests atomic.Int32
+	server := newIDServer(t, existing, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, missing, err := repo.GetMany(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+	if len(items) != 300 || len(missing) != 0 || items[299].ID != ids[299] {
+		t.Fatalf("unexpected result: %d items, missing %v", len(items), missing)
+	}
+	if requests.Load() < 2 {
+		t.Fatalf("expected the filter to be split, got %d requests", requests.Load())
+	}
+	match := func(id string) string { return Eq("id", id) }
+	for _, chunk := range idFilterChunks(ids, match) {
+		if len(Or(mapSlice(chunk, match)...)) > maxIDFilterLength {
+			t.Fatalf("chunk filter exceeds the limit")
+		}
+	}
+}
+
+func TestLoaderCoalescesConcurrentLoads(t *testing.T) {
+	var requests atomic.Int32
+	server := newIDServer(t, map[string]bool{"a": true, "b": true, "c": true}, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	loader := repo.NewLoader(LoaderOptions{Wait: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b", "c", "a", "missing"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, err := loader.Load(context.Background(), id)
+			if id == "missing" {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil || item.ID != id {
+				t.Errorf("Load %s: %+v, %v", id, item, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests.Load() != 1 {
+		t.Fatalf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestLoaderDispatchesFullBatches(t *testing.T) {
+	var requests atomic.Int32
+	server := newIDServer(t, map[string]bool{"a": true, "b": true}, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	loader := repo.NewLoader(LoaderOptions{Wait: time.Hour, MaxBatch: 1})
+
+	for _, id := range []string{"a", "b"} {
+		if _, err := loader.Load(context.Background(), id); err != nil {
+			t.Fatalf("Load %s: %v", id, err)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests.Load())
+	}
+}
@@ -0,0 +1,206 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Hooks bundles lifecycle callbacks so packages can ship them as a unit and
+// attach them with Repository.Use. Nil fields are ignored.
+//
+// Before-hooks may modify the record that is about to be sent or abort the
+// write by returning an error. After-hooks receive the record returned by the
+// server; an error from an after-hook is returned to the caller together with
+// that record, since the write has already been applied.
+//
+// Every after-hook runs even when an earlier one fails; their errors are joined.
+//
+// Hooks run around the repository's writes, including UpsertBy, DeleteWhere,
+// UpdateWhere and Query.Delete, where they run once per matching record and
+// may run concurrently. Patch and UpdateWhere have no full record to pass, so
+// they run BeforePatch instead of BeforeUpdate; a guard that must cover every
+// update should be registered for both. Upsert and operations queued on a
+// Batch with BatchCreate, BatchUpdate, BatchUpsert or BatchDelete bypass hooks.
+type Hooks[T any] struct {
+	BeforeCreate func(ctx context.Context, record *T) error
+	AfterCreate  func(ctx context.Context, record *T) error
+	BeforeUpdate func(ctx context.Context, id string, record *T) error
+	BeforePatch  func(ctx context.Context, id string, patch *FieldPatch) error
+	AfterUpdate  func(ctx context.Context, record *T) error
+	BeforeDelete func(ctx context.Context, id string) error
+	AfterDelete  func(ctx context.Context, id string) error
+}
+
+// hookRegistry holds the hooks registered on a repository, in registration order.
+type hookRegistry[T any] struct {
+	mu    sync.RWMutex
+	hooks []Hooks[T]
+}
+
+// Use registers every non-nil hook in hooks. Hooks run in registration order.
+func (r *Repository[T]) Use(hooks Hooks[T]) {
+	r.hooks.mu.Lock()
+	defer r.hooks.mu.Unlock()
+	r.hooks.hooks = append(r.hooks.hooks, hooks)
+}
+
+// BeforeCreate registers a hook that runs before a record is created.
+func (r *Repository[T]) BeforeCreate(fn func(ctx context.Context, record *T) error) {
+	r.Use(Hooks[T]{BeforeCreate: fn})
+}
+
+// AfterCreate registers a hook that runs with the created record.
+func (r *Repository[T]) AfterCreate(fn func(ctx context.Context, record *T) error) {
+	r.Use(Hooks[T]{AfterCreate: fn})
+}
+
+// BeforeUpdate registers a hook that runs before a record is updated.
+func (r *Repository[T]) BeforeUpdate(fn func(ctx context.Context, id string, record *T) error) {
+	r.Use(Hooks[T]{BeforeUpdate: fn})
+}
+
+// BeforePatch registers a hook that runs before a FieldPatch is applied to the
+// record with id. The hook may add changes to patch or abort the write.
+func (r *Repository[T]) BeforePatch(fn func(ctx context.Context, id string, patch *FieldPatch) error) {
+	r.Use(Hooks[T]{BeforePatch: fn})
+}
+
+// AfterUpdate registers a hook that runs with the updated record.
+func (r *Repository[T]) AfterUpdate(fn func(ctx context.Context, record *T) error) {
+	r.Use(Hooks[T]{AfterUpdate: fn})
+}
+
+// BeforeDelete registers a hook that runs before a record is deleted.
+func (r *Repository[T]) BeforeDelete(fn func(ctx context.Context, id string) error) {
+	r.Use(Hooks[T]{BeforeDelete: fn})
+}
+
+// AfterDelete registers a hook that runs after a record was deleted.
+func (r *Repository[T]) AfterDelete(fn func(ctx context.Context, id string) error) {
+	r.Use(Hooks[T]{AfterDelete: fn})
+}
+
+func (h *hookRegistry[T]) snapshot() []Hooks[T] {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.hooks
+}
+
+func (h *hookRegistry[T]) beforeCreate(ctx context.Context, record *T) error {
+	for _, hooks := range h.snapshot() {
+		if hooks.BeforeCreate == nil {
+			continue
+		}
+		if err := hooks.BeforeCreate(ctx, record); err != nil {
+			return fmt.Errorf("before create hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hookRegistry[T]) afterCreate(ctx context.Context, record *T) error {
+	var errs []error
+	for _, hooks := range h.snapshot() {
+		if hooks.AfterCreate == nil {
+			continue
+		}
+		if err := hooks.AfterCreate(ctx, record); err != nil {
+			errs = append(errs, fmt.Errorf("after create hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *hookRegistry[T]) beforeUpdate(ctx context.Context, id string, record *T) error {
+	for _, hooks := range h.snapshot() {
+		if hooks.BeforeUpdate == nil {
+			continue
+		}
+		if err := hooks.BeforeUpdate(ctx, id, record); err != nil {
+			return fmt.Errorf("before update hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hookRegistry[T]) beforePatch(ctx context.Context, id string, patch *FieldPatch) error {
+	for _, hooks := range h.snapshot() {
+		if hooks.BeforePatch == nil {
+			continue
+		}
+		if err := hooks.BeforePatch(ctx, id, patch); err != nil {
+			return fmt.Errorf("before patch hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hookRegistry[T]) afterUpdate(ctx context.Context, record *T) error {
+	var errs []error
+	for _, hooks := range h.snapshot() {
+		if hooks.AfterUpdate == nil {
+			continue
+		}
+		if err := hooks.AfterUpdate(ctx, record); err != nil {
+			errs = append(errs, fmt.Errorf("after update hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *hookRegistry[T]) beforeDelete(ctx context.Context, id string) error {
+	for _, hooks := range h.snapshot() {
+		if hooks.BeforeDelete == nil {
+			continue
+		}
+		if err := hooks.BeforeDelete(ctx, id); err != nil {
+			return fmt.Errorf("before delete hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hookRegistry[T]) afterDelete(ctx context.Context, id string) error {
+	var errs []error
+	for _, hooks := range h.snapshot() {
+		if hooks.AfterDelete == nil {
+			continue
+		}
+		if err := hooks.AfterDelete(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("after delete hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hookedCreate runs the create hooks around send.
+func (r *Repository[T]) hookedCreate(ctx context.Context, record T, send func(T) (*T, error)) (*T, error) {
+	if err := r.hooks.beforeCreate(ctx, &record); err != nil {
+		return nil, err
+	}
+	created, err := send(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.hooks.afterCreate(ctx, created); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// hookedUpdate runs the update hooks around send.
+func (r *Repository[T]) hookedUpdate(ctx context.Context, id string, record T, send func(T) (*T, error)) (*T, error) {
+	if err := r.hooks.beforeUpdate(ctx, id, &record); err != nil {
+		return nil, err
+	}
+	updated, err := send(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.hooks.afterUpdate(ctx, updated); err != nil {
+		return updated, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,245 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRepositoryCreateHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body := string(readBody(t, r)); body != `{"id":"","name":"stamped"}` {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"abc","name":"stamped"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var order []string
+	repo.BeforeCreate(func(ctx context.Context, record *testRecord) error {
+		order = append(order, "before")
+		record.Name = "stamped"
+		return nil
+	})
+	repo.Use(Hooks[testRecord]{
+		AfterCreate: func(ctx context.Context, record *testRecord) error {
+			order = append(order, "after:"+record.ID)
+			return nil
+		},
+	})
+
+	got, err := repo.Create(context.Background(), testRecord{Name: "original"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got.ID != "abc" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+	if len(order) != 2 || order[0] != "before" || order[1] != "after:abc" {
+		t.Fatalf("unexpected hook order: %v", order)
+	}
+}
+
+func TestRepositoryBeforeHookAbortsWrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	invalid := errors.New("name is required")
+	repo.BeforeUpdate(func(ctx context.Context, id string, record *testRecord) error {
+		if record.Name == "" {
+			return invalid
+		}
+		return nil
+	})
+	repo.BeforeDelete(func(ctx context.Context, id string) error {
+		return invalid
+	})
+
+	if _, err := repo.Update(context.Background(), "abc", testRecord{}); !errors.Is(err, invalid) {
+		t.Fatalf("expected hook error from Update, got %v", err)
+	}
+	if err := repo.Delete(context.Background(), "abc"); !errors.Is(err, invalid) {
+		t.Fatalf("expected hook error from Delete, got %v", err)
+	}
+}
+
+func TestRepositoryUpdateAndDeleteHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			_, _ = w.Write([]byte(`{"id":"abc","name":"from server"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var updated []string
+	var deleted []string
+	repo.Use(Hooks[testRecord]{
+		BeforeUpdate: func(ctx context.Context, id string, record *testRecord) error {
+			updated = append(updated, "before:"+id)
+			return nil
+		},
+		AfterUpdate: func(ctx context.Context, record *testRecord) error {
+			updated = append(updated, "after:"+record.Name)
+			return nil
+		},
+		BeforeDelete: func(ctx context.Context, id string) error {
+			deleted = append(deleted, "before:"+id)
+			return nil
+		},
+		AfterDelete: func(ctx context.Context, id string) error {
+			deleted = append(deleted, "after:"+id)
+			return nil
+		},
+	})
+
+	if _, err := repo.Update(context.Background(), "abc", testRecord{Name: "local"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(updated) != 2 || updated[0] != "before:abc" || updated[1] != "after:from server" {
+		t.Fatalf("unexpected update hooks: %v", updated)
+	}
+
+	if err := repo.Delete(context.Background(), "abc"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != "before:abc" || deleted[1] != "after:abc" {
+		t.Fatalf("unexpected delete hooks: %v", deleted)
+	}
+}
+
+func TestRepositoryAfterHookErrorReturnsRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"abc","name":"created"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	publishErr := errors.New("publish failed")
+	auditErr := errors.New("audit failed")
+	repo.AfterCreate(func(ctx context.Context, record *testRecord) error {
+		return publishErr
+	})
+	repo.AfterCreate(func(ctx context.Context, record *testRecord) error {
+		return auditErr
+	})
+
+	got, err := repo.Create(context.Background(), testRecord{Name: "created"})
+	if !errors.Is(err, publishErr) || !errors.Is(err, auditErr) {
+		t.Fatalf("expected both after hook errors, got %v", err)
+	}
+	if got == nil || got.ID != "abc" {
+		t.Fatalf("expected created record alongside error, got %+v", got)
+	}
+}
+
+func TestRepositoryPatchRunsBeforePatchHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body := string(readBody(t, r)); body != `{"editor":"svc","views+":1}` {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"abc","name":"patched"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var after []string
+	repo.BeforePatch(func(ctx context.Context, id string, patch *FieldPatch) error {
+		patch.Set("editor", "svc")
+		return nil
+	})
+	repo.AfterUpdate(func(ctx context.Context, record *testRecord) error {
+		after = append(after, record.Name)
+		return nil
+	})
+
+	patch := Patch().Inc("views", 1)
+	if _, err := repo.Patch(context.Background(), "abc", patch); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if patch.Len() != 1 {
+		t.Fatalf("hook changed the caller's patch")
+	}
+	if len(after) != 1 || after[0] != "patched" {
+		t.Fatalf("unexpected after hooks: %v", after)
+	}
+}
+
+func TestRepositoryDeleteWhereRunsHooks(t *testing.T) {
+	for _, batchAllowed := range []bool{true, false} {
+		state := &bulkTestServer{batchAllowed: batchAllowed}
+		server := httptest.NewServer(state.handle(t))
+
+		client := newTestClient(t, server).(*authenticatedClient)
+		client.creds = Credentials{Email: "admin@example.com", Password: "password"}
+		client.authEndpoint = userAuthEndpoint
+		repo := NewRepository[testRecord](client, "test")
+
+		locked := errors.New("record is locked")
+		var mu sync.Mutex
+		var deleted []string
+		repo.BeforeDelete(func(ctx context.Context, id string) error {
+			if id == "r2" {
+				return locked
+			}
+			return nil
+		})
+		repo.AfterDelete(func(ctx context.Context, id string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, id)
+			return nil
+		})
+
+		res, err := repo.DeleteWhere(context.Background(), "status='old'", BulkOptions{})
+		server.Close()
+		if err != nil {
+			t.Fatalf("DeleteWhere: %v", err)
+		}
+		if res.Affected != 4 || res.Failed != 1 || !errors.Is(res.Errors["r2"], locked) {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+		slices.Sort(deleted)
+		if want := []string{"r1", "r3", "r4", "r5"}; !slices.Equal(deleted, want) {
+			t.Fatalf("batch=%v: unexpected after hooks: %v", batchAllowed, deleted)
+		}
+		if !batchAllowed && slices.Contains(state.singleDeletes, "r2") {
+			t.Fatalf("locked record was deleted")
+		}
+	}
+}
+
+func TestRepositoryUpsertSkipsHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]any{{"status": 200, "body": map[string]any{"id": "abc"}}})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	repo.Use(Hooks[testRecord]{
+		BeforeCreate: func(ctx context.Context, record *testRecord) error { return errors.New("unexpected hook") },
+		BeforeUpdate: func(ctx context.Context, id string, record *testRecord) error { return errors.New("unexpected hook") },
+	})
+
+	if _, err := repo.Upsert(context.Background(), testRecord{ID: "abc"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+}
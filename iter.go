@@ -0,0 +1,70 @@
+package pbclient
+
+import (
+	"context"
+	"iter"
+)
+
+// defaultIterPerPage is the page size used by iterators when ListOptions.PerPage is unset.
+const defaultIterPerPage = 200
+
+// Pages lazily walks result pages starting at opts.Page (or the first page).
+// The total count is skipped, so iteration ends at the first short page.
+// An error is yielded once and ends the iteration.
+func (r *Repository[T]) Pages(ctx context.Context, opts ListOptions) iter.Seq2[*ListResult[T], error] {
+	return func(yield func(*ListResult[T], error) bool) {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		if opts.PerPage <= 0 {
+			opts.PerPage = defaultIterPerPage
+		}
+		opts.SkipTotal = true
+
+		for {
+			res, err := r.List(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(res, nil) {
+				return
+			}
+			if isLastPage(res, opts.PerPage) {
+				return
+			}
+			opts.Page++
+		}
+	}
+}
+
+// All lazily yields every record matching opts, fetching pages on demand.
+// Breaking out of the loop stops further requests.
+func (r *Repository[T]) All(ctx context.Context, opts ListOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range r.Pages(ctx, opts) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// isLastPage reports whether no further pages follow res.
+func isLastPage[T any](res *ListResult[T], perPage int) bool {
+	// The server may cap perPage below the requested size.
+	if res.PerPage > 0 && res.PerPage < perPage {
+		perPage = res.PerPage
+	}
+	if len(res.Items) == 0 || len(res.Items) < perPage {
+		return true
+	}
+	return res.TotalPages > 0 && res.Page >= res.TotalPages
+}
@@ -0,0 +1,99 @@
+package pbclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagedServer serves total records named "0".."total-1" using skipTotal semantics.
+func newPagedServer(t *testing.T, total int, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		if q.Get("skipTotal") != "1" {
+			t.Fatalf("expected skipTotal=1, got %q", q.Get("skipTotal"))
+		}
+		page := parseIntDefault(q.Get("page"), 1)
+		perPage := parseIntDefault(q.Get("perPage"), 30)
+
+		items := make([]testRecord, 0, perPage)
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, testRecord{ID: strconv.Itoa(i), Name: fmt.Sprintf("item-%d", i)})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items":      items,
+			"page":       page,
+			"perPage":    perPage,
+			"totalItems": -1,
+			"totalPages": -1,
+		})
+	}))
+}
+
+func TestRepositoryAllWalksEveryPage(t *testing.T) {
+	var requests int
+	server := newPagedServer(t, 5, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var ids []string
+	for rec, err := range repo.All(context.Background(), ListOptions{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		ids = append(ids, rec.ID)
+	}
+
+	if len(ids) != 5 || ids[0] != "0" || ids[4] != "4" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestRepositoryAllStopsOnBreak(t *testing.T) {
+	var requests int
+	server := newPagedServer(t, 100, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	count := 0
+	for _, err := range repo.All(context.Background(), ListOptions{PerPage: 10}) {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		count++
+		if count == 15 {
+			break
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests after early break, got %d", requests)
+	}
+}
+
+func TestRepositoryPagesYieldsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var gotErr error
+	for _, err := range repo.Pages(context.Background(), ListOptions{}) {
+		gotErr = err
+	}
+	if gotErr != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", gotErr)
+	}
+}
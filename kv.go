@@ -1,14 +1,12 @@
 package pbclient
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 )
 
@@ -52,11 +50,6 @@ func (s KVStore) Set(ctx context.Context, key string, value interface{}) error {
 		return fmt.Errorf("marshal value: %w", err)
 	}
 
-	id, err := s.getRecordIDByKey(ctx, key)
-	if err != nil && !errors.Is(err, ErrNotFound) {
-		return err
-	}
-
 	// Use interface{} for value to support both text and JSON field types
 	payload := map[string]interface{}{
 		"key":     key,
@@ -64,25 +57,9 @@ func (s KVStore) Set(ctx context.Context, key string, value interface{}) error {
 		"appname": s.appName,
 	}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
-	}
-
-	method := http.MethodPost
-	path := fmt.Sprintf("/api/collections/%s/records", url.PathEscape(s.collection))
-	if id != "" {
-		method = http.MethodPatch
-		path += "/" + url.PathEscape(id)
-	}
-
-	resp, err := s.client.Do(ctx, method, path, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return decodeJSONResponse(resp, nil)
+	repo := NewRepository[map[string]interface{}](s.client, s.collection)
+	_, err = repo.UpsertBy(ctx, s.filterByKey(key), payload)
+	return err
 }
 
 // Get fetches a value for the given key as raw JSON bytes.
@@ -97,40 +74,23 @@ func (s KVStore) Get(ctx context.Context, key string) (json.RawMessage, error) {
 		return nil, errors.New("key is required")
 	}
 
-	params := url.Values{}
-	params.Set("filter", s.filterByKey(key))
-	params.Set("perPage", "1")
-
-	path := fmt.Sprintf("/api/collections/%s/records?%s", url.PathEscape(s.collection), params.Encode())
-	resp, err := s.client.Do(ctx, http.MethodGet, path, nil)
+	repo := NewRepository[struct {
+		Value json.RawMessage `json:"value"`
+	}](s.client, s.collection)
+	item, err := repo.First(ctx, s.filterByKey(key), ListOptions{})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var payload struct {
-		Items []struct {
-			Value json.RawMessage `json:"value"`
-		} `json:"items"`
-	}
-
-	if err := decodeJSONResponse(resp, &payload); err != nil {
-		return nil, err
-	}
-
-	if len(payload.Items) == 0 {
-		return nil, ErrNotFound
-	}
 
 	// Try to unmarshal as direct JSON first (for JSON field type)
 	var raw json.RawMessage
-	if err := json.Unmarshal(payload.Items[0].Value, &raw); err == nil {
+	if err := json.Unmarshal(item.Value, &raw); err == nil {
 		return raw, nil
 	}
 
 	// Fall back to treating it as a JSON-encoded string (for text field type)
 	var str string
-	if err := json.Unmarshal(payload.Items[0].Value, &str); err != nil {
+	if err := json.Unmarshal(item.Value, &str); err != nil {
 		return nil, fmt.Errorf("decode value: %w", err)
 	}
 	return json.RawMessage(str), nil
@@ -217,14 +177,16 @@ func (s KVStore) Delete(ctx context.Context, key string) error {
 
 // Exists returns true if a key exists.
 func (s KVStore) Exists(ctx context.Context, key string) (bool, error) {
-	id, err := s.getRecordIDByKey(ctx, key)
-	if err != nil {
-		if errors.Is(err, ErrNotFound) {
-			return false, nil
-		}
-		return false, err
+	if s.client == nil {
+		return false, errors.New("kv client is nil")
 	}
-	return id != "", nil
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return false, errors.New("key is required")
+	}
+
+	return NewRepository[struct{}](s.client, s.collection).Exists(ctx, s.filterByKey(key))
 }
 
 // List returns all keys, optionally filtered by prefix.
@@ -236,48 +198,26 @@ func (s KVStore) List(ctx context.Context, prefix string) ([]string, error) {
 	keys := make([]string, 0)
 	prefix = strings.TrimSpace(prefix)
 
-	page := 1
-	for {
-		params := url.Values{}
-		params.Set("page", strconv.Itoa(page))
-		params.Set("perPage", "200")
-		params.Set("fields", "id,key")
-		filter := s.appNameFilter()
-		if prefix != "" {
-			prefixFilter := fmt.Sprintf("key~'%s%%'", escapeFilterValue(prefix))
-			filter = And(filter, prefixFilter)
-		}
-		if filter != "" {
-			params.Set("filter", filter)
-		}
+	filter := s.appNameFilter()
+	if prefix != "" {
+		prefixFilter := fmt.Sprintf("key~'%s%%'", escapeFilterValue(prefix))
+		filter = And(filter, prefixFilter)
+	}
 
-		path := fmt.Sprintf("/api/collections/%s/records?%s", url.PathEscape(s.collection), params.Encode())
-		resp, err := s.client.Do(ctx, http.MethodGet, path, nil)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		var payload struct {
-			Items []struct {
-				Key string `json:"key"`
-			} `json:"items"`
-			Page       int `json:"page"`
-			TotalPages int `json:"totalPages"`
-		}
+	repo := NewRepository[struct {
+		Key string `json:"key"`
+	}](s.client, s.collection)
+	opts := ListOptions{
+		PerPage: 200,
+		Filter:  filter,
+		Fields:  []string{"id", "key"},
+	}
 
-		if err := decodeJSONResponse(resp, &payload); err != nil {
+	for item, err := range repo.All(ctx, opts) {
+		if err != nil {
 			return nil, err
 		}
-
-		for _, item := range payload.Items {
-			keys = append(keys, item.Key)
-		}
-
-		if payload.TotalPages == 0 || payload.Page >= payload.TotalPages {
-			break
-		}
-		page++
+		keys = append(keys, item.Key)
 	}
 
 	return keys, nil
@@ -294,33 +234,7 @@ func (s KVStore) getRecordIDByKey(ctx context.Context, key string) (string, erro
 		return "", errors.New("key is required")
 	}
 
-	params := url.Values{}
-	params.Set("filter", s.filterByKey(key))
-	params.Set("perPage", "1")
-	params.Set("fields", "id")
-
-	path := fmt.Sprintf("/api/collections/%s/records?%s", url.PathEscape(s.collection), params.Encode())
-	resp, err := s.client.Do(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	var payload struct {
-		Items []struct {
-			ID string `json:"id"`
-		} `json:"items"`
-	}
-
-	if err := decodeJSONResponse(resp, &payload); err != nil {
-		return "", err
-	}
-
-	if len(payload.Items) == 0 {
-		return "", ErrNotFound
-	}
-
-	return payload.Items[0].ID, nil
+	return NewRepository[struct{}](s.client, s.collection).lookupID(ctx, s.filterByKey(key))
 }
 
 func (s KVStore) appNameFilter() string {
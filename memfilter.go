@@ -0,0 +1,385 @@
+package pbclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterExpr is a parsed PocketBase filter evaluated by MemoryStore.
+type filterExpr interface {
+	match(values map[string]any) bool
+}
+
+type logicalExpr struct {
+	and         bool
+	left, right filterExpr
+}
+
+func (e logicalExpr) match(values map[string]any) bool {
+	if e.and {
+		return e.left.match(values) && e.right.match(values)
+	}
+	return e.left.match(values) || e.right.match(values)
+}
+
+type operand struct {
+	field   string
+	literal any
+}
+
+func (o operand) value(values map[string]any) any {
+	if o.field != "" {
+		return lookupField(values, o.field)
+	}
+	return o.literal
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e comparisonExpr) match(values map[string]any) bool {
+	left, right := e.left.value(values), e.right.value(values)
+	switch e.op {
+	case "=":
+		return valuesEqual(left, right)
+	case "!=":
+		return !valuesEqual(left, right)
+	case ">":
+		return compareValues(left, right) > 0
+	case ">=":
+		return compareValues(left, right) >= 0
+	case "<":
+		return compareValues(left, right) < 0
+	case "<=":
+		return compareValues(left, right) <= 0
+	case "~":
+		return likeMatch(left, right)
+	case "!~":
+		return !likeMatch(left, right)
+	}
+	return false
+}
+
+// parseFilter parses filter; an empty filter yields a nil expression that matches everything.
+func parseFilter(filter string) (filterExpr, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type filterTokenKind int
+
+const (
+	tokenOperand filterTokenKind = iota
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenOpen
+	tokenClose
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+var filterOperators = []string{"!=", ">=", "<=", "!~", "=", ">", "<", "~"}
+
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: tokenOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: tokenClose, text: ")"})
+			i++
+		case strings.HasPrefix(filter[i:], "&&"):
+			tokens = append(tokens, filterToken{kind: tokenAnd, text: "&&"})
+			i += 2
+		case strings.HasPrefix(filter[i:], "||"):
+			tokens = append(tokens, filterToken{kind: tokenOr, text: "||"})
+			i += 2
+		case c == '?':
+			return nil, fmt.Errorf("any-of operators are not supported at offset %d", i)
+		case c == '\'' || c == '"':
+			text, n, err := readFilterString(filter[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, text: text})
+			i += n
+		default:
+			if op := matchFilterOperator(filter[i:]); op != "" {
+				tokens = append(tokens, filterToken{kind: tokenOperator, text: op})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(filter) && !strings.ContainsRune(" \t\r\n()'\"=!<>~&|?", rune(filter[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, filterToken{kind: tokenOperand, text: filter[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func matchFilterOperator(s string) string {
+	for _, op := range filterOperators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// readFilterString reads a quoted literal with backslash escapes and returns it with the number of bytes consumed.
+func readFilterString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string %s", s)
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokenOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokenAnd {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{and: true, left: left, right: right}
+	}
+}
+
+func (p *filterParser) parsePrimary() (filterExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+	if tok.kind == tokenOpen {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, ok := p.peek(); !ok || tok.kind != tokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peek()
+	if !ok || op.kind != tokenOperator {
+		return nil, fmt.Errorf("expected operator after %q", tok.text)
+	}
+	p.pos++
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpr{op: op.text, left: left, right: right}, nil
+}
+
+func (p *filterParser) parseOperand() (operand, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return operand{}, fmt.Errorf("unexpected end of filter")
+	}
+	p.pos++
+
+	switch tok.kind {
+	case tokenString:
+		return operand{literal: tok.text}, nil
+	case tokenOperand:
+	default:
+		return operand{}, fmt.Errorf("unexpected %q", tok.text)
+	}
+
+	switch tok.text {
+	case "true":
+		return operand{literal: true}, nil
+	case "false":
+		return operand{literal: false}, nil
+	case "null":
+		return operand{literal: nil}, nil
+	}
+	if n, err := strconv.ParseFloat(tok.text, 64); err == nil {
+		return operand{literal: n}, nil
+	}
+	if strings.HasPrefix(tok.text, "@") {
+		return operand{}, fmt.Errorf("%s is not supported", tok.text)
+	}
+	return operand{field: tok.text}, nil
+}
+
+// lookupField resolves a field name, following dots into nested JSON objects.
+func lookupField(values map[string]any, field string) any {
+	var current any = values
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = obj[part]
+	}
+	return current
+}
+
+func isEmptyValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func valuesEqual(a, b any) bool {
+	// PocketBase treats null and empty values alike.
+	if a == nil || b == nil {
+		return isEmptyValue(a) && isEmptyValue(b)
+	}
+	return compareValues(a, b) == 0
+}
+
+// compareValues orders two JSON values. Numbers compare numerically when both
+// sides are numbers; everything else compares by its string form, so text that
+// looks numeric compares as text, as in PocketBase.
+func compareValues(a, b any) int {
+	if fa, ok := numericValue(a); ok {
+		if fb, ok := numericValue(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(stringValue(a), stringValue(b))
+}
+
+func numericValue(v any) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+func stringValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// likeMatch implements the ~ operator: a case-insensitive contains check, or a
+// LIKE pattern when the value contains % wildcards.
+func likeMatch(value, pattern any) bool {
+	p := stringValue(pattern)
+	if !strings.Contains(p, "%") {
+		p = "%" + p + "%"
+	}
+	parts := strings.Split(p, "%")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("(?is)^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(stringValue(value))
+}
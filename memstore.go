@@ -0,0 +1,342 @@
+package pbclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	memoryDefaultPerPage = 30
+	memoryMaxPerPage     = 1000
+	memoryIDLength       = 15
+	memoryIDAlphabet     = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// MemoryStore is an in-memory RecordStore for tests. It mimics PocketBase:
+// records get 15-character ids and created/updated timestamps, updates merge
+// the sent fields into the stored record, List paginates and sorts, and missing
+// records yield ErrNotFound.
+//
+// Filters support the comparison operators =, !=, >, >=, <, <=, ~ and !~,
+// combined with && and || and grouped with parentheses, which covers the
+// output of Eq, Neq, Gt, Gte, Lt, Lte, And and Or. Expand is ignored.
+type MemoryStore[T any] struct {
+	mu      sync.RWMutex
+	records map[string]map[string]any
+	order   []string
+	now     func() time.Time
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore[T any]() *MemoryStore[T] {
+	return &MemoryStore[T]{
+		records: make(map[string]map[string]any),
+		now:     time.Now,
+	}
+}
+
+// Get returns the record with id.
+func (s *MemoryStore[T]) Get(ctx context.Context, id string, opts ...GetOptions) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	var fields []string
+	for _, opt := range opts {
+		if len(opt.Fields) > 0 {
+			fields = opt.Fields
+		}
+	}
+
+	var out T
+	if err := decodeMemoryRecord(projectFields(values, fields), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// List returns a page of records matching opts.
+func (s *MemoryStore[T]) List(ctx context.Context, opts ListOptions) (*ListResult[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid filter: %v", ErrBadRequest, err)
+	}
+	sorts, err := parseSort(opts.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sort: %v", ErrBadRequest, err)
+	}
+
+	page := opts.Page
+	if page <= 0 {
+		page = 1
+	}
+	perPage := opts.PerPage
+	if perPage <= 0 {
+		perPage = memoryDefaultPerPage
+	}
+	perPage = min(perPage, memoryMaxPerPage)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]map[string]any, 0)
+	for _, id := range s.order {
+		values := s.records[id]
+		if filter == nil || filter.match(values) {
+			matched = append(matched, values)
+		}
+	}
+	if len(sorts) > 0 {
+		slices.SortStableFunc(matched, func(a, b map[string]any) int {
+			for _, sf := range sorts {
+				c := compareValues(lookupField(a, sf.field), lookupField(b, sf.field))
+				if sf.desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	start := min((page-1)*perPage, len(matched))
+	end := min(start+perPage, len(matched))
+
+	items := make([]T, 0, end-start)
+	for _, values := range matched[start:end] {
+		var item T
+		if err := decodeMemoryRecord(projectFields(values, opts.Fields), &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	result := &ListResult[T]{
+		Items:      items,
+		Page:       page,
+		PerPage:    perPage,
+		TotalItems: len(matched),
+		TotalPages: (len(matched) + perPage - 1) / perPage,
+	}
+	if opts.SkipTotal {
+		result.TotalItems = -1
+		result.TotalPages = -1
+	}
+	return result, nil
+}
+
+// Create stores a new record, generating its id unless one is set.
+func (s *MemoryStore[T]) Create(ctx context.Context, record T) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	values, err := encodeMemoryRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := values["id"].(string)
+	if id == "" {
+		if id, err = s.newID(); err != nil {
+			return nil, err
+		}
+	} else if _, exists := s.records[id]; exists {
+		return nil, &ValidationError{
+			Status:  http.StatusBadRequest,
+			Message: "Failed to create record.",
+			Fields:  map[string]FieldError{"id": {Code: "validation_not_unique", Message: "Value must be unique."}},
+		}
+	}
+
+	now := NewDateTime(s.now()).String()
+	values["id"] = id
+	values["created"] = now
+	values["updated"] = now
+
+	s.records[id] = values
+	s.order = append(s.order, id)
+
+	var out T
+	if err := decodeMemoryRecord(values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Update merges the fields of record into the stored record with id.
+func (s *MemoryStore[T]) Update(ctx context.Context, id string, record T) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id is required")
+	}
+
+	values, err := encodeMemoryRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	merged := make(map[string]any, len(stored)+len(values))
+	for name, value := range stored {
+		merged[name] = value
+	}
+	for name, value := range values {
+		if systemFields[name] {
+			continue
+		}
+		merged[name] = value
+	}
+	merged["updated"] = NewDateTime(s.now()).String()
+	s.records[id] = merged
+
+	var out T
+	if err := decodeMemoryRecord(merged, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Delete removes the record with id.
+func (s *MemoryStore[T]) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(id) == "" {
+		return errors.New("id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.records, id)
+	s.order = slices.DeleteFunc(s.order, func(existing string) bool { return existing == id })
+	return nil
+}
+
+// newID returns an unused random id in PocketBase's format. Callers must hold s.mu.
+func (s *MemoryStore[T]) newID() (string, error) {
+	for {
+		buf := make([]byte, memoryIDLength)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate id: %w", err)
+		}
+		for i, b := range buf {
+			buf[i] = memoryIDAlphabet[int(b)%len(memoryIDAlphabet)]
+		}
+		if id := string(buf); s.records[id] == nil {
+			return id, nil
+		}
+	}
+}
+
+func encodeMemoryRecord(record any) (map[string]any, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("record must encode as a JSON object: %w", err)
+	}
+	if values == nil {
+		values = make(map[string]any)
+	}
+	return values, nil
+}
+
+func decodeMemoryRecord(values map[string]any, dst any) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("decode record: %w", err)
+	}
+	return nil
+}
+
+// projectFields keeps only the named top-level fields, like the fields query parameter.
+func projectFields(values map[string]any, fields []string) map[string]any {
+	if len(fields) == 0 {
+		return values
+	}
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		name, _, _ := strings.Cut(strings.TrimSpace(field), ":")
+		if name == "*" {
+			return values
+		}
+		if value, ok := values[name]; ok {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+type sortField struct {
+	field string
+	desc  bool
+}
+
+func parseSort(sort string) ([]sortField, error) {
+	var fields []sortField
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sf := sortField{field: part}
+		switch part[0] {
+		case '-':
+			sf = sortField{field: part[1:], desc: true}
+		case '+':
+			sf.field = part[1:]
+		}
+		if sf.field == "" || strings.HasPrefix(sf.field, "@") {
+			return nil, fmt.Errorf("unsupported sort field %q", part)
+		}
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
@@ -0,0 +1,191 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type memTodo struct {
+	BaseRecord
+	Title    string  `json:"title"`
+	Priority float64 `json:"priority"`
+	Done     bool    `json:"done"`
+}
+
+func seedMemoryStore(t *testing.T) *MemoryStore[memTodo] {
+	t.Helper()
+
+	store := NewMemoryStore[memTodo]()
+	for i, title := range []string{"write docs", "fix bug", "Review PR", "deploy"} {
+		_, err := store.Create(context.Background(), memTodo{Title: title, Priority: float64(i + 1), Done: i%2 == 0})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	return store
+}
+
+func TestMemoryStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[memTodo]()
+
+	created, err := store.Create(ctx, memTodo{Title: "first"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(created.ID) != 15 || created.Created.IsZero() || !created.Created.Equal(created.Updated.Time) {
+		t.Fatalf("expected generated id and timestamps, got %+v", created)
+	}
+
+	_, err = store.Create(ctx, memTodo{BaseRecord: BaseRecord{ID: created.ID}})
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrBadRequest) || !errors.As(err, &validationErr) || validationErr.Fields["id"].Code != "validation_not_unique" {
+		t.Fatalf("expected a ValidationError for duplicate id, got %v", err)
+	}
+
+	updated, err := store.Update(ctx, created.ID, memTodo{Title: "renamed", Done: true})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Title != "renamed" || !updated.Done || updated.ID != created.ID || !updated.Created.Equal(created.Created.Time) {
+		t.Fatalf("unexpected updated record: %+v", updated)
+	}
+
+	got, err := store.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != "renamed" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	if err := store.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if _, err := store.Update(ctx, created.ID, memTodo{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from Update, got %v", err)
+	}
+	if err := store.Delete(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from Delete, got %v", err)
+	}
+}
+
+func TestMemoryStoreListFilters(t *testing.T) {
+	store := seedMemoryStore(t)
+
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{"Eq", Eq("title", "fix bug"), []string{"fix bug"}},
+		{"Neq", Neq("title", "fix bug"), []string{"write docs", "Review PR", "deploy"}},
+		{"Gt", Gt("priority", "2"), []string{"Review PR", "deploy"}},
+		{"Lte", Lte("priority", "2"), []string{"write docs", "fix bug"}},
+		{"Bool", Eq("done", "true"), []string{"write docs", "Review PR"}},
+		{"And", And(Gte("priority", "2"), Eq("done", "false")), []string{"fix bug", "deploy"}},
+		{"Or", Or(Eq("title", "deploy"), Lt("priority", "2")), []string{"write docs", "deploy"}},
+		{"Nested", And(Or(Eq("title", "deploy"), Eq("title", "fix bug")), "priority>2"), []string{"deploy"}},
+		{"Like", "title ~ 'review'", []string{"Review PR"}},
+		{"NotLike", "title !~ 'e'", []string{"fix bug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := store.List(context.Background(), ListOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []string
+			for _, item := range res.Items {
+				got = append(got, item.Title)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := store.List(context.Background(), ListOptions{Filter: "title = "}); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest for invalid filter, got %v", err)
+	}
+}
+
+func TestMemoryStoreListPaginationAndSort(t *testing.T) {
+	store := seedMemoryStore(t)
+
+	res, err := store.List(context.Background(), ListOptions{Page: 2, PerPage: 3, Sort: "-priority"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if res.TotalItems != 4 || res.TotalPages != 2 || res.Page != 2 || res.PerPage != 3 {
+		t.Fatalf("unexpected pagination: %+v", res)
+	}
+	if len(res.Items) != 1 || res.Items[0].Title != "write docs" {
+		t.Fatalf("unexpected items: %+v", res.Items)
+	}
+
+	res, err = store.List(context.Background(), ListOptions{Sort: "done,-title", Fields: []string{"title"}, SkipTotal: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if res.TotalItems != -1 || res.TotalPages != -1 {
+		t.Fatalf("expected skipped totals, got %+v", res)
+	}
+	want := []string{"fix bug", "deploy", "write docs", "Review PR"}
+	for i, item := range res.Items {
+		if item.Title != want[i] || item.ID != "" {
+			t.Fatalf("item %d: got %+v, want title %q and no id", i, item, want[i])
+		}
+	}
+}
+
+func TestMemoryStoreWorksWithRecordStoreConsumers(t *testing.T) {
+	var store RecordStore[memTodo] = seedMemoryStore(t)
+
+	res, err := store.List(context.Background(), ListOptions{PerPage: 1})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(res.Items) != 1 || res.TotalPages != 4 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMemoryStoreComparesTextAsText(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[memTodo]()
+	for _, title := range []string{"01", "1.0", "9", "10"} {
+		if _, err := store.Create(ctx, memTodo{Title: title}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{Eq("title", "1"), 0},
+		{Eq("title", "01"), 1},
+		{"title > '9'", 0},
+		{"title < '9'", 3},
+	}
+	for _, tt := range tests {
+		res, err := store.List(ctx, ListOptions{Filter: tt.filter})
+		if err != nil {
+			t.Fatalf("List %s: %v", tt.filter, err)
+		}
+		if len(res.Items) != tt.want {
+			t.Fatalf("%s: got %d records, want %d", tt.filter, len(res.Items), tt.want)
+		}
+	}
+}
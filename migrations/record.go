@@ -18,6 +18,8 @@ type Record struct {
 }
 
 // PBTime handles the PocketBase datetime format returned by the API (with a space instead of T).
+// Unlike pbclient.DateTime it encodes as RFC 3339, and the zero time as
+// "0001-01-01T00:00:00Z", as it always has.
 type PBTime struct {
 	time.Time
 }
@@ -342,29 +342,21 @@ func (r *Runner) fetchApplied(ctx context.Context) ([]Record, error) {
 	repo := pbclient.NewRepository[Record](r.client, r.logCollection)
 	all := make([]Record, 0)
 
-	page := 1
-	for {
-		opts := pbclient.ListOptions{
-			Page:    page,
-			PerPage: 200,
-			Fields:  []string{"id", "appname", "name", "applied_at"},
-		}
+	opts := pbclient.ListOptions{
+		PerPage: 200,
+		Fields:  []string{"id", "appname", "name", "applied_at"},
+	}
 
-		// Filter by appname if set
-		if r.appName != "" {
-			opts.Filter = pbclient.Eq("appname", r.appName)
-		}
+	// Filter by appname if set
+	if r.appName != "" {
+		opts.Filter = pbclient.Eq("appname", r.appName)
+	}
 
-		res, err := repo.List(ctx, opts)
+	for rec, err := range repo.All(ctx, opts) {
 		if err != nil {
 			return nil, err
 		}
-
-		all = append(all, res.Items...)
-		if res.TotalPages == 0 || res.Page >= res.TotalPages {
-			break
-		}
-		page++
+		all = append(all, rec)
 	}
 
 	sort.SliceStable(all, func(i, j int) bool {
@@ -454,3 +454,27 @@ func parseIntDefault(raw string, fallback int) int {
 	}
 	return val
 }
+
+func TestPBTimeKeepsRFC3339Encoding(t *testing.T) {
+	applied := PBTime{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	data, err := json.Marshal(Record{Name: "001_init", AppliedAt: applied})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"applied_at":"2024-01-02T03:04:05Z"`) {
+		t.Fatalf("unexpected encoding: %s", data)
+	}
+
+	zero, err := json.Marshal(PBTime{})
+	if err != nil {
+		t.Fatalf("marshal zero: %v", err)
+	}
+	if string(zero) != `"0001-01-01T00:00:00Z"` {
+		t.Fatalf("unexpected zero encoding: %s", zero)
+	}
+
+	var decoded PBTime
+	if err := json.Unmarshal([]byte(`"2024-01-02 03:04:05.000Z"`), &decoded); err != nil || !decoded.Equal(applied.Time) {
+		t.Fatalf("decode PocketBase format: %v, %v", decoded, err)
+	}
+}
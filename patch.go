@@ -0,0 +1,141 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// FieldPatch is a set of field changes applied with PocketBase's field modifiers.
+// Modifiers are evaluated by the server, so counters and list edits do not race
+// with concurrent writers.
+type FieldPatch struct {
+	fields map[string]any
+}
+
+// Patch starts an empty FieldPatch:
+//
+//	Patch().Inc("views", 1).Append("tags", "x").Remove("related", id).Set("title", s)
+func Patch() *FieldPatch {
+	return &FieldPatch{fields: make(map[string]any)}
+}
+
+// Set assigns value to field.
+func (p *FieldPatch) Set(field string, value any) *FieldPatch {
+	p.fields[field] = value
+	return p
+}
+
+// Inc adds n to a number field ("field+").
+func (p *FieldPatch) Inc(field string, n float64) *FieldPatch {
+	p.fields[field+"+"] = n
+	return p
+}
+
+// Dec subtracts n from a number field ("field-").
+func (p *FieldPatch) Dec(field string, n float64) *FieldPatch {
+	p.fields[field+"-"] = n
+	return p
+}
+
+// Append adds values to the end of a multi-select, relation or file field ("field+").
+func (p *FieldPatch) Append(field string, values ...any) *FieldPatch {
+	p.fields[field+"+"] = values
+	return p
+}
+
+// Prepend adds values to the start of a multi-select, relation or file field ("+field").
+func (p *FieldPatch) Prepend(field string, values ...any) *FieldPatch {
+	p.fields["+"+field] = values
+	return p
+}
+
+// Remove deletes values from a multi-select, relation or file field ("field-").
+func (p *FieldPatch) Remove(field string, values ...any) *FieldPatch {
+	p.fields[field+"-"] = values
+	return p
+}
+
+// Len returns the number of changes in the patch.
+func (p *FieldPatch) Len() int {
+	return len(p.fields)
+}
+
+// clone returns a copy of the patch that can be changed independently.
+func (p *FieldPatch) clone() *FieldPatch {
+	return &FieldPatch{fields: maps.Clone(p.fields)}
+}
+
+// MarshalJSON encodes the patch as a PocketBase update body.
+func (p *FieldPatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.fields)
+}
+
+// Patch applies patch to the record with id and returns the updated record.
+// BeforePatch hooks run on a copy of patch, so they never change the caller's value.
+func (r *Repository[T]) Patch(ctx context.Context, id string, patch *FieldPatch) (*T, error) {
+	if patch == nil || patch.Len() == 0 {
+		return nil, errors.New("patch is empty")
+	}
+	patch = patch.clone()
+	if err := r.hooks.beforePatch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+	updated, err := r.patch(ctx, id, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.hooks.afterUpdate(ctx, updated); err != nil {
+		return updated, err
+	}
+	return updated, nil
+}
+
+// UpdateFields sends only the named JSON fields of record, leaving all other
+// fields untouched on the server. Zero values are sent even for omitempty fields.
+func (r *Repository[T]) UpdateFields(ctx context.Context, id string, record T, fields ...string) (*T, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	return r.hookedUpdate(ctx, id, record, func(record T) (*T, error) {
+		values, err := jsonFieldValues(record)
+		if err != nil {
+			return nil, err
+		}
+
+		payload := make(map[string]any, len(fields))
+		for _, field := range fields {
+			value, ok := values[field]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", field)
+			}
+			payload[field] = value
+		}
+		return r.patch(ctx, id, payload)
+	})
+}
+
+// UpdateChanged sends only the fields whose values differ between before and after.
+// System fields such as id, created and updated are never sent. When nothing
+// changed, no request is made and after is returned as is.
+func (r *Repository[T]) UpdateChanged(ctx context.Context, id string, before, after T) (*T, error) {
+	changes, err := changedFields(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return &after, nil
+	}
+
+	return r.hookedUpdate(ctx, id, after, func(after T) (*T, error) {
+		// Hooks may have modified the record, so diff again.
+		changes, err := changedFields(before, after)
+		if err != nil {
+			return nil, err
+		}
+		return r.patch(ctx, id, changes)
+	})
+}
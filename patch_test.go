@@ -0,0 +1,125 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFieldPatchMarshalsModifiers(t *testing.T) {
+	patch := Patch().
+		Inc("views", 1).
+		Dec("stock", 2).
+		Append("tags", "x").
+		Prepend("queue", "first").
+		Remove("related", "r1", "r2").
+		Set("title", "hello")
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"+queue":["first"],"related-":["r1","r2"],"stock-":2,"tags+":["x"],"title":"hello","views+":1}`
+	if string(data) != want {
+		t.Fatalf("got %s\nwant %s", data, want)
+	}
+}
+
+func TestRepositoryPatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/collections/test/records/abc" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if body := string(readBody(t, r)); body != `{"views+":1}` {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"abc","name":"counted"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	got, err := repo.Patch(context.Background(), "abc", Patch().Inc("views", 1))
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if got.Name != "counted" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	if _, err := repo.Patch(context.Background(), "abc", Patch()); err == nil {
+		t.Fatalf("expected error for empty patch")
+	}
+}
+
+func TestRepositoryUpdateFieldsSendsZeroValues(t *testing.T) {
+	type settings struct {
+		ID      string `json:"id"`
+		Title   string `json:"title,omitempty"`
+		Enabled bool   `json:"enabled,omitempty"`
+		Limit   int    `json:"limit,omitempty"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body := string(readBody(t, r)); body != `{"enabled":false,"limit":0}` {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"s1","title":"kept"}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[settings](newTestClient(t, server), "settings")
+
+	got, err := repo.UpdateFields(context.Background(), "s1", settings{Title: "ignored"}, "enabled", "limit")
+	if err != nil {
+		t.Fatalf("UpdateFields: %v", err)
+	}
+	if got.Title != "kept" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	if _, err := repo.UpdateFields(context.Background(), "s1", settings{}, "missing"); err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
+
+func TestRepositoryUpdateChangedSendsDiff(t *testing.T) {
+	type base struct {
+		ID      string `json:"id"`
+		Updated string `json:"updated"`
+	}
+	type article struct {
+		base
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+		Views int      `json:"views"`
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if body := string(readBody(t, r)); body != `{"tags":["a","b"]}` {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"a1","title":"same","tags":["a","b"]}`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[article](newTestClient(t, server), "articles")
+	before := article{base: base{ID: "a1", Updated: "t1"}, Title: "same", Tags: []string{"a"}, Views: 3}
+	after := before
+	after.Tags = []string{"a", "b"}
+	after.Updated = "t2"
+
+	if _, err := repo.UpdateChanged(context.Background(), "a1", before, after); err != nil {
+		t.Fatalf("UpdateChanged: %v", err)
+	}
+	if _, err := repo.UpdateChanged(context.Background(), "a1", after, after); err != nil {
+		t.Fatalf("UpdateChanged without changes: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
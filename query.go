@@ -0,0 +1,183 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+)
+
+// maxQueryPerPage bounds the page size used to serve a Limit in one request.
+const maxQueryPerPage = 500
+
+// Query is a fluent builder for list requests, created with Repository.Query.
+// Builder methods modify and return the query; a Query must not be modified
+// concurrently.
+//
+//	todos, err := repo.Query().
+//		Where(Eq("done", "false")).
+//		OrderBy("-created").
+//		Limit(50).Offset(100).
+//		All(ctx)
+type Query[T any] struct {
+	repo    *Repository[T]
+	filters []string
+	sort    []string
+	fields  []string
+	expand  []string
+	limit   int
+	offset  int
+}
+
+// Query starts a query on the repository's collection.
+func (r *Repository[T]) Query() *Query[T] {
+	return &Query[T]{repo: r}
+}
+
+// Where adds filters; all filters added to a query must match.
+func (q *Query[T]) Where(filters ...string) *Query[T] {
+	q.filters = append(q.filters, filters...)
+	return q
+}
+
+// OrderBy appends sort fields, e.g. "-created" or "title".
+func (q *Query[T]) OrderBy(fields ...string) *Query[T] {
+	q.sort = append(q.sort, fields...)
+	return q
+}
+
+// Select limits the returned fields.
+func (q *Query[T]) Select(fields ...string) *Query[T] {
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+// Expand adds relations to expand.
+func (q *Query[T]) Expand(relations ...string) *Query[T] {
+	q.expand = append(q.expand, relations...)
+	return q
+}
+
+// Limit caps the number of returned records; zero means no limit.
+func (q *Query[T]) Limit(n int) *Query[T] {
+	q.limit = max(n, 0)
+	return q
+}
+
+// Offset skips the first n matching records.
+func (q *Query[T]) Offset(n int) *Query[T] {
+	q.offset = max(n, 0)
+	return q
+}
+
+// Filter returns the combined filter expression.
+func (q *Query[T]) Filter() string {
+	return And(q.filters...)
+}
+
+// Iter lazily yields the matching records. Limit and Offset are mapped to
+// page and perPage, fetching the following page when the window crosses a
+// page boundary.
+func (q *Query[T]) Iter(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		perPage := q.perPage()
+		opts := q.listOptions()
+		opts.Page = q.offset/perPage + 1
+		opts.PerPage = perPage
+
+		skip := q.offset % perPage
+		remaining := q.limit
+		for page, err := range q.repo.Pages(ctx, opts) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if page.PerPage > 0 && page.PerPage != perPage {
+				var zero T
+				yield(zero, errors.New("server changed the page size; offset cannot be applied"))
+				return
+			}
+
+			items := page.Items[min(skip, len(page.Items)):]
+			skip = 0
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				if q.limit > 0 {
+					remaining--
+					if remaining == 0 {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// All returns the matching records.
+func (q *Query[T]) All(ctx context.Context) ([]T, error) {
+	items := make([]T, 0)
+	for item, err := range q.Iter(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// First returns the first matching record after Offset, or ErrNotFound.
+func (q *Query[T]) First(ctx context.Context) (*T, error) {
+	first := *q
+	first.limit = 1
+	for item, err := range first.Iter(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		return &item, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Count returns the number of records All would return, taking Limit and Offset into account.
+func (q *Query[T]) Count(ctx context.Context) (int, error) {
+	total, err := q.repo.Count(ctx, q.Filter())
+	if err != nil {
+		return 0, err
+	}
+	total = max(total-q.offset, 0)
+	if q.limit > 0 {
+		total = min(total, q.limit)
+	}
+	return total, nil
+}
+
+// Delete deletes every matching record with DeleteWhere. Limit and Offset are
+// not supported, since the batch delete works on the whole filter. A query
+// without conditions returns ErrEmptyFilter unless opts.AllowAll is set.
+func (q *Query[T]) Delete(ctx context.Context, opts BulkOptions) (*BulkResult, error) {
+	if q.limit > 0 || q.offset > 0 {
+		return nil, errors.New("delete does not support limit or offset")
+	}
+	return q.repo.DeleteWhere(ctx, q.Filter(), opts)
+}
+
+func (q *Query[T]) listOptions() ListOptions {
+	return ListOptions{
+		Filter: q.Filter(),
+		Sort:   strings.Join(q.sort, ","),
+		Fields: q.fields,
+		Expand: q.expand,
+	}
+}
+
+// perPage picks the page size: the limit itself when the window is aligned to
+// it, so a single request serves the query, and the iterator default otherwise.
+func (q *Query[T]) perPage() int {
+	if q.limit > 0 && q.limit <= maxQueryPerPage && q.offset%q.limit == 0 {
+		return q.limit
+	}
+	return defaultIterPerPage
+}
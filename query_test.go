@@ -0,0 +1,140 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestQueryBuildsListParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("filter"); got != "(done='false' && priority>2)" {
+			t.Fatalf("unexpected filter: %q", got)
+		}
+		if got := q.Get("sort"); got != "-created,title" {
+			t.Fatalf("unexpected sort: %q", got)
+		}
+		if got := q.Get("fields"); got != "id,name" {
+			t.Fatalf("unexpected fields: %q", got)
+		}
+		if got := q.Get("expand"); got != "author" {
+			t.Fatalf("unexpected expand: %q", got)
+		}
+		if q.Get("page") != "1" || q.Get("perPage") != "10" {
+			t.Fatalf("unexpected pagination: %s", r.URL.RawQuery)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": []testRecord{{ID: "a"}}, "page": 1, "perPage": 10})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, err := repo.Query().
+		Where(Eq("done", "false")).
+		Where(Gt("priority", "2")).
+		OrderBy("-created", "title").
+		Select("id", "name").
+		Expand("author").
+		Limit(10).
+		All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "a" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestQueryLimitOffsetAlignedUsesSinglePage(t *testing.T) {
+	var requests int
+	server := newPagedServer(t, 450, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, err := repo.Query().Limit(50).Offset(100).All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(items) != 50 || items[0].ID != "100" || items[49].ID != "149" {
+		t.Fatalf("unexpected window: %d items from %s", len(items), items[0].ID)
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestQueryLimitOffsetCrossesPageBoundary(t *testing.T) {
+	var requests int
+	server := newPagedServer(t, 450, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	items, err := repo.Query().Offset(190).Limit(30).All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(items) != 30 {
+		t.Fatalf("expected 30 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.ID != strconv.Itoa(190+i) {
+			t.Fatalf("item %d: got id %s", i, item.ID)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+
+	first, err := repo.Query().Offset(7).First(context.Background())
+	if err != nil {
+		t.Fatalf("First: %v", err)
+	}
+	if first.ID != "7" {
+		t.Fatalf("unexpected first record: %+v", first)
+	}
+
+	if _, err := repo.Query().Offset(1000).First(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestQueryCountAppliesLimitAndOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"items": []testRecord{{ID: "a"}}, "page": 1, "perPage": 1, "totalItems": 120, "totalPages": 120})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	tests := []struct {
+		query *Query[testRecord]
+		want  int
+	}{
+		{repo.Query(), 120},
+		{repo.Query().Offset(100), 20},
+		{repo.Query().Offset(100).Limit(10), 10},
+		{repo.Query().Offset(200), 0},
+	}
+	for i, tt := range tests {
+		got, err := tt.query.Count(context.Background())
+		if err != nil {
+			t.Fatalf("case %d: Count: %v", i, err)
+		}
+		if got != tt.want {
+			t.Fatalf("case %d: got %d, want %d", i, got, tt.want)
+		}
+	}
+
+	if _, err := repo.Query().Limit(5).Delete(context.Background(), BulkOptions{}); err == nil {
+		t.Fatalf("expected error deleting with a limit")
+	}
+	if _, err := repo.Query().Delete(context.Background(), BulkOptions{}); !errors.Is(err, ErrEmptyFilter) {
+		t.Fatalf("expected ErrEmptyFilter deleting without conditions, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrNotView is returned by NewViewRepository when the collection is not a view collection.
+var ErrNotView = errors.New("collection is not a view")
+
+// viewSortField orders view records when no sort is given. Views have no
+// created or updated columns by default and no implicit order, so paging
+// without a sort could skip or repeat rows.
+const viewSortField = "id"
+
+// ReadOnlyRepository exposes only the read operations of a Repository, for
+// PocketBase view collections and other collections that must not be written.
+// It lists by id unless a sort is given, since views have no default order and
+// usually no created column. Views may use non-string id columns; decode them
+// into a matching type in T. With a numeric id field in T, GetMany matches ids
+// as numbers, and cursors keep the id as the server returned it.
+type ReadOnlyRepository[T any] struct {
+	repo *Repository[T]
+}
+
+// NewReadOnlyRepository creates a read-only repository bound to a collection.
+func NewReadOnlyRepository[T any](client AuthenticatedClient, collection string, opts ...RepositoryOption) *ReadOnlyRepository[T] {
+	return &ReadOnlyRepository[T]{repo: NewRepository[T](client, collection, opts...)}
+}
+
+// NewViewRepository creates a read-only repository after checking with the
+// collection schema that collection is a view. It returns ErrNotView otherwise.
+// Reading the schema requires superuser credentials.
+func NewViewRepository[T any](ctx context.Context, client AuthenticatedClient, collection string, opts ...RepositoryOption) (*ReadOnlyRepository[T], error) {
+	r := NewReadOnlyRepository[T](client, collection, opts...)
+	schema, err := r.repo.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != "view" {
+		return nil, fmt.Errorf("%w: %s is a %s collection", ErrNotView, schema.Name, schema.Type)
+	}
+	return r, nil
+}
+
+// Get fetches a single record by ID.
+func (r *ReadOnlyRepository[T]) Get(ctx context.Context, id string, opts ...GetOptions) (*T, error) {
+	return r.repo.Get(ctx, id, opts...)
+}
+
+// List returns a page of records using the provided options.
+func (r *ReadOnlyRepository[T]) List(ctx context.Context, opts ListOptions) (*ListResult[T], error) {
+	return r.repo.List(ctx, withViewSort(opts))
+}
+
+// First returns the first record matching filter, or ErrNotFound when nothing matches.
+func (r *ReadOnlyRepository[T]) First(ctx context.Context, filter string, opts ListOptions) (*T, error) {
+	return r.repo.First(ctx, filter, withViewSort(opts))
+}
+
+// FindOne returns the single record matching filter; see Repository.FindOne.
+func (r *ReadOnlyRepository[T]) FindOne(ctx context.Context, filter string, opts ListOptions) (*T, error) {
+	return r.repo.FindOne(ctx, filter, opts)
+}
+
+// GetMany fetches records by id; see Repository.GetMany.
+func (r *ReadOnlyRepository[T]) GetMany(ctx context.Context, ids []string, opts ...GetOptions) ([]T, []string, error) {
+	return r.repo.GetMany(ctx, ids, opts...)
+}
+
+// Count returns the number of records matching filter.
+func (r *ReadOnlyRepository[T]) Count(ctx context.Context, filter string) (int, error) {
+	return r.repo.Count(ctx, filter)
+}
+
+// Exists reports whether any record matches filter.
+func (r *ReadOnlyRepository[T]) Exists(ctx context.Context, filter string) (bool, error) {
+	return r.repo.Exists(ctx, filter)
+}
+
+// Pages lazily walks result pages; see Repository.Pages.
+func (r *ReadOnlyRepository[T]) Pages(ctx context.Context, opts ListOptions) iter.Seq2[*ListResult[T], error] {
+	return r.repo.Pages(ctx, withViewSort(opts))
+}
+
+// All lazily yields every record matching opts; see Repository.All.
+func (r *ReadOnlyRepository[T]) All(ctx context.Context, opts ListOptions) iter.Seq2[T, error] {
+	return r.repo.All(ctx, withViewSort(opts))
+}
+
+// GetFullList fetches every record matching opts concurrently; see Repository.GetFullList.
+func (r *ReadOnlyRepository[T]) GetFullList(ctx context.Context, opts ListOptions, concurrency int) ([]T, error) {
+	return r.repo.GetFullList(ctx, withViewSort(opts), concurrency)
+}
+
+// ListCursor returns a page using keyset pagination, by id unless opts.SortField is set.
+func (r *ReadOnlyRepository[T]) ListCursor(ctx context.Context, opts CursorOptions) (*CursorPage[T], error) {
+	return r.repo.ListCursor(ctx, withViewCursor(opts))
+}
+
+// CursorPages walks pages with keyset pagination, by id unless opts.SortField is set.
+func (r *ReadOnlyRepository[T]) CursorPages(ctx context.Context, opts CursorOptions) iter.Seq2[*CursorPage[T], error] {
+	return r.repo.CursorPages(ctx, withViewCursor(opts))
+}
+
+func withViewSort(opts ListOptions) ListOptions {
+	if opts.Sort == "" {
+		opts.Sort = viewSortField
+	}
+	return opts
+}
+
+func withViewCursor(opts CursorOptions) CursorOptions {
+	if opts.SortField == "" {
+		opts.SortField = viewSortField
+	}
+	return opts
+}
@@ -0,0 +1,129 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+type viewRow struct {
+	ID    int    `json:"id"`
+	Total string `json:"total"`
+}
+
+func TestNewViewRepositoryChecksCollectionType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/collections/stats":
+			_, _ = w.Write([]byte(`{"id":"pbc_1","name":"stats","type":"view","fields":[]}`))
+		case "/api/collections/posts":
+			_, _ = w.Write([]byte(`{"id":"pbc_2","name":"posts","type":"base","fields":[]}`))
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	if _, err := NewViewRepository[viewRow](context.Background(), client, "stats"); err != nil {
+		t.Fatalf("NewViewRepository: %v", err)
+	}
+	if _, err := NewViewRepository[viewRow](context.Background(), client, "posts"); !errors.Is(err, ErrNotView) {
+		t.Fatalf("expected ErrNotView, got %v", err)
+	}
+}
+
+func TestReadOnlyRepositoryDefaultsToIDOrder(t *testing.T) {
+	var sorts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		sorts = append(sorts, q.Get("sort"))
+		if filter := q.Get("filter"); filter != "" && filter != "(id=2 || id=1 || id=3)" {
+			t.Fatalf("expected numeric id filter, got %s", filter)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items":   []map[string]any{{"id": 1, "total": "10"}, {"id": 2, "total": "20"}},
+			"page":    1,
+			"perPage": 30,
+		})
+	}))
+	defer server.Close()
+
+	repo := NewReadOnlyRepository[viewRow](newTestClient(t, server), "stats")
+	ctx := context.Background()
+
+	if _, err := repo.List(ctx, ListOptions{}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := repo.List(ctx, ListOptions{Sort: "-total"}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	for row, err := range repo.All(ctx, ListOptions{}) {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		if row.ID == 0 {
+			t.Fatalf("expected numeric id to decode, got %+v", row)
+		}
+	}
+
+	if len(sorts) != 3 || sorts[0] != "id" || sorts[1] != "-total" || sorts[2] != "id" {
+		t.Fatalf("unexpected sorts: %v", sorts)
+	}
+
+	rows, missing, err := repo.GetMany(ctx, []string{"2", "1", "3"})
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != 2 || rows[1].ID != 1 || len(missing) != 1 || missing[0] != "3" {
+		t.Fatalf("unexpected GetMany result: %+v, missing %v", rows, missing)
+	}
+}
+
+func TestReadOnlyRepositoryCursorPagesNumericIDs(t *testing.T) {
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("sort") != "id" {
+			t.Fatalf("unexpected sort: %s", q.Get("sort"))
+		}
+		filters = append(filters, q.Get("filter"))
+
+		var items []map[string]any
+		switch len(filters) {
+		case 1:
+			items = []map[string]any{{"id": 9, "total": "a"}, {"id": 10, "total": "b"}}
+		case 2:
+			items = []map[string]any{{"id": 11, "total": "c"}, {"id": 12, "total": "d"}}
+		case 3:
+			items = []map[string]any{{"id": 13, "total": "e"}}
+		default:
+			t.Fatalf("unexpected extra request")
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 2})
+	}))
+	defer server.Close()
+
+	repo := NewReadOnlyRepository[viewRow](newTestClient(t, server), "stats")
+
+	var ids []int
+	for page, err := range repo.CursorPages(context.Background(), CursorOptions{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("CursorPages: %v", err)
+		}
+		for _, row := range page.Items {
+			ids = append(ids, row.ID)
+		}
+	}
+
+	if want := []int{9, 10, 11, 12, 13}; !slices.Equal(ids, want) {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if want := []string{"", "id>10", "id>12"}; !slices.Equal(filters, want) {
+		t.Fatalf("unexpected filters: %q", filters)
+	}
+}
@@ -0,0 +1,28 @@
+package pbclient
+
+import "context"
+
+// RecordReader is the read half of RecordStore. *ReadOnlyRepository[T]
+// implements only this part.
+type RecordReader[T any] interface {
+	Get(ctx context.Context, id string, opts ...GetOptions) (*T, error)
+	List(ctx context.Context, opts ListOptions) (*ListResult[T], error)
+}
+
+// RecordStore is the set of record operations services usually depend on.
+// *Repository[T] implements it against PocketBase and *MemoryStore[T] in memory,
+// so code written against RecordStore can be unit tested without a server.
+type RecordStore[T any] interface {
+	RecordReader[T]
+	Create(ctx context.Context, record T) (*T, error)
+	Update(ctx context.Context, id string, record T) (*T, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	_ RecordStore[struct{}] = (*Repository[struct{}])(nil)
+	_ RecordStore[struct{}] = (*MemoryStore[struct{}])(nil)
+	_ RecordStore[struct{}] = (*CachedRepository[struct{}])(nil)
+
+	_ RecordReader[struct{}] = (*ReadOnlyRepository[struct{}])(nil)
+)
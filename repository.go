@@ -9,22 +9,79 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 )
 
+// ErrMultipleMatches is returned by FindOne when more than one record matches the filter.
+var ErrMultipleMatches = errors.New("multiple records match")
+
 // Repository exposes CRUD helpers for PocketBase collections.
 type Repository[T any] struct {
 	client     AuthenticatedClient
 	collection string
+	opts       repositoryOptions
+	hooks      hookRegistry[T]
+	autoFields []string
+	schema     schemaCache
+}
+
+// RepositoryOption configures optional Repository settings.
+type RepositoryOption func(*repositoryOptions)
+
+type repositoryOptions struct {
+	versionField   string
+	autoFields     bool
+	validateSchema bool
+}
+
+// WithVersionField makes optimistic concurrency checks compare the named numeric
+// field instead of the "updated" timestamp. The field is incremented on every
+// conditional update.
+func WithVersionField(field string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.versionField = strings.TrimSpace(field)
+	}
+}
+
+// WithAutoFields derives the fields parameter of Get and List calls from the
+// json tags of T, so columns the struct ignores are not downloaded. Embedded
+// structs are promoted and the expand type of Expanded is projected as
+// "expand.<relation>.<field>". Calls that set Fields or AllFields are unaffected.
+func WithAutoFields() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.autoFields = true
+	}
 }
 
 // NewRepository creates a repository bound to a PocketBase collection.
-func NewRepository[T any](client AuthenticatedClient, collection string) *Repository[T] {
-	return &Repository[T]{
+func NewRepository[T any](client AuthenticatedClient, collection string, opts ...RepositoryOption) *Repository[T] {
+	r := &Repository[T]{
 		client:     client,
 		collection: strings.TrimSpace(collection),
 	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&r.opts)
+		}
+	}
+	if r.opts.autoFields {
+		r.autoFields = projectionFields(reflect.TypeFor[T]())
+	}
+
+	return r
+}
+
+// rawRepository returns a repository over the same collection that decodes
+// records as raw JSON, keeping r's automatic field projection.
+func (r *Repository[T]) rawRepository() *Repository[json.RawMessage] {
+	return &Repository[json.RawMessage]{
+		client:     r.client,
+		collection: r.collection,
+		autoFields: r.autoFields,
+	}
 }
 
 // ListOptions describes pagination and filtering options for list calls.
@@ -34,6 +91,21 @@ type ListOptions struct {
 	Filter  string
 	Sort    string
 	Fields  []string
+	// Expand lists relations to expand, including nested paths ("author.org")
+	// and back-relations ("comments_via_post").
+	Expand []string
+	// SkipTotal skips the COUNT query; TotalItems and TotalPages are then reported as -1.
+	SkipTotal bool
+	// AllFields requests every field, overriding WithAutoFields.
+	AllFields bool
+}
+
+// GetOptions describes optional parameters for fetching a single record.
+type GetOptions struct {
+	Fields []string
+	Expand []string
+	// AllFields requests every field, overriding WithAutoFields.
+	AllFields bool
 }
 
 // ListResult contains a page of items with pagination metadata.
@@ -46,7 +118,7 @@ type ListResult[T any] struct {
 }
 
 // Get fetches a single record by ID.
-func (r *Repository[T]) Get(ctx context.Context, id string) (*T, error) {
+func (r *Repository[T]) Get(ctx context.Context, id string, opts ...GetOptions) (*T, error) {
 	if r.client == nil {
 		return nil, errors.New("repository client is nil")
 	}
@@ -57,7 +129,28 @@ func (r *Repository[T]) Get(ctx context.Context, id string) (*T, error) {
 		return nil, errors.New("id is required")
 	}
 
+	params := url.Values{}
+	fields := r.autoFields
+	for _, opt := range opts {
+		if len(opt.Fields) > 0 {
+			fields = opt.Fields
+		}
+		if opt.AllFields {
+			fields = nil
+		}
+		if len(opt.Expand) > 0 {
+			params.Set("expand", strings.Join(opt.Expand, ","))
+		}
+	}
+	if len(fields) > 0 {
+		params.Set("fields", strings.Join(fields, ","))
+	}
+
 	path := fmt.Sprintf("/api/collections/%s/records/%s", url.PathEscape(r.collection), url.PathEscape(id))
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+
 	resp, err := r.client.Do(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -93,8 +186,18 @@ func (r *Repository[T]) List(ctx context.Context, opts ListOptions) (*ListResult
 	if opts.Sort != "" {
 		params.Set("sort", opts.Sort)
 	}
-	if len(opts.Fields) > 0 {
-		params.Set("fields", strings.Join(opts.Fields, ","))
+	fields := opts.Fields
+	if len(fields) == 0 && !opts.AllFields {
+		fields = r.autoFields
+	}
+	if len(fields) > 0 {
+		params.Set("fields", strings.Join(fields, ","))
+	}
+	if len(opts.Expand) > 0 {
+		params.Set("expand", strings.Join(opts.Expand, ","))
+	}
+	if opts.SkipTotal {
+		params.Set("skipTotal", "1")
 	}
 
 	path := fmt.Sprintf("/api/collections/%s/records", url.PathEscape(r.collection))
@@ -134,8 +237,81 @@ func (r *Repository[T]) List(ctx context.Context, opts ListOptions) (*ListResult
 	}, nil
 }
 
+// First returns the first record matching filter, or ErrNotFound when nothing matches.
+// Sort and Fields are taken from opts; pagination options are ignored.
+func (r *Repository[T]) First(ctx context.Context, filter string, opts ListOptions) (*T, error) {
+	items, err := r.find(ctx, filter, opts, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// FindOne returns the single record matching filter. It returns ErrNotFound when
+// nothing matches and ErrMultipleMatches when the filter is not unique.
+func (r *Repository[T]) FindOne(ctx context.Context, filter string, opts ListOptions) (*T, error) {
+	items, err := r.find(ctx, filter, opts, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > 1 {
+		return nil, ErrMultipleMatches
+	}
+	return &items[0], nil
+}
+
+// Count returns the number of records matching filter. Only the id field of a
+// single record is transferred; the total comes from the server's count query.
+func (r *Repository[T]) Count(ctx context.Context, filter string) (int, error) {
+	res, err := r.List(ctx, ListOptions{
+		Page:    1,
+		PerPage: 1,
+		Filter:  filter,
+		Fields:  []string{"id"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.TotalItems, nil
+}
+
+// Exists reports whether any record matches filter, skipping the count query.
+func (r *Repository[T]) Exists(ctx context.Context, filter string) (bool, error) {
+	_, err := r.lookupID(ctx, filter)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// find lists up to limit records matching filter without counting the total.
+func (r *Repository[T]) find(ctx context.Context, filter string, opts ListOptions, limit int) ([]T, error) {
+	opts.Page = 1
+	opts.PerPage = limit
+	opts.Filter = And(filter, opts.Filter)
+	opts.SkipTotal = true
+
+	res, err := r.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return res.Items, nil
+}
+
 // Create inserts a new record.
 func (r *Repository[T]) Create(ctx context.Context, record T) (*T, error) {
+	return r.hookedCreate(ctx, record, func(record T) (*T, error) {
+		return r.create(ctx, record)
+	})
+}
+
+func (r *Repository[T]) create(ctx context.Context, record T) (*T, error) {
 	if r.client == nil {
 		return nil, errors.New("repository client is nil")
 	}
@@ -143,6 +319,10 @@ func (r *Repository[T]) Create(ctx context.Context, record T) (*T, error) {
 		return nil, errors.New("collection is required")
 	}
 
+	if err := r.validatePayload(ctx, record, true); err != nil {
+		return nil, err
+	}
+
 	payload, err := json.Marshal(record)
 	if err != nil {
 		return nil, fmt.Errorf("marshal record: %w", err)
@@ -164,6 +344,13 @@ func (r *Repository[T]) Create(ctx context.Context, record T) (*T, error) {
 
 // Update patches an existing record.
 func (r *Repository[T]) Update(ctx context.Context, id string, record T) (*T, error) {
+	return r.hookedUpdate(ctx, id, record, func(record T) (*T, error) {
+		return r.patch(ctx, id, record)
+	})
+}
+
+// patch sends payload as a PATCH to the record with id and decodes the updated record.
+func (r *Repository[T]) patch(ctx context.Context, id string, payload any) (*T, error) {
 	if r.client == nil {
 		return nil, errors.New("repository client is nil")
 	}
@@ -174,13 +361,17 @@ func (r *Repository[T]) Update(ctx context.Context, id string, record T) (*T, er
 		return nil, errors.New("id is required")
 	}
 
-	payload, err := json.Marshal(record)
+	if err := r.validatePayload(ctx, payload, false); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshal record: %w", err)
 	}
 
 	path := fmt.Sprintf("/api/collections/%s/records/%s", url.PathEscape(r.collection), url.PathEscape(id))
-	resp, err := r.client.Do(ctx, http.MethodPatch, path, bytes.NewReader(payload))
+	resp, err := r.client.Do(ctx, http.MethodPatch, path, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +386,16 @@ func (r *Repository[T]) Update(ctx context.Context, id string, record T) (*T, er
 
 // Delete removes a record by ID.
 func (r *Repository[T]) Delete(ctx context.Context, id string) error {
+	if err := r.hooks.beforeDelete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.delete(ctx, id); err != nil {
+		return err
+	}
+	return r.hooks.afterDelete(ctx, id)
+}
+
+func (r *Repository[T]) delete(ctx context.Context, id string) error {
 	if r.client == nil {
 		return errors.New("repository client is nil")
 	}
@@ -224,6 +425,28 @@ func (r *Repository[T]) Delete(ctx context.Context, id string) error {
 	return mapHTTPError(resp.StatusCode, body)
 }
 
+// recordFields marshals a record and returns its top-level JSON fields.
+func recordFields(record any) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("decode record fields: %w", err)
+	}
+	return values, nil
+}
+
+// stringField returns the named field as a string, or "" when it is missing or not a string.
+func stringField(values map[string]json.RawMessage, name string) string {
+	var s string
+	if err := json.Unmarshal(values[name], &s); err != nil {
+		return ""
+	}
+	return s
+}
+
 // decodeJSONResponse reads and decodes the response, mapping HTTP errors to sentinel values.
 func decodeJSONResponse(resp *http.Response, dst any) error {
 	body, err := io.ReadAll(resp.Body)
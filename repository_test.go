@@ -219,6 +219,83 @@ func TestRepositoryDeleteHTTPError(t *testing.T) {
 	}
 }
 
+func TestRepositoryFirstAndFindOne(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("skipTotal") != "1" {
+			t.Fatalf("expected skipTotal=1, got %q", q.Get("skipTotal"))
+		}
+		var items []testRecord
+		switch q.Get("filter") {
+		case "name='one'":
+			items = []testRecord{{ID: "1", Name: "one"}}
+		case "name='dup'":
+			items = []testRecord{{ID: "2", Name: "dup"}, {ID: "3", Name: "dup"}}
+		}
+		if perPage := parseIntDefault(q.Get("perPage"), 30); len(items) > perPage {
+			items = items[:perPage]
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 1, "totalItems": -1, "totalPages": -1})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	ctx := context.Background()
+
+	got, err := repo.First(ctx, Eq("name", "one"), ListOptions{})
+	if err != nil || got.ID != "1" {
+		t.Fatalf("First: %+v, %v", got, err)
+	}
+	if _, err := repo.First(ctx, Eq("name", "missing"), ListOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if got, err := repo.First(ctx, Eq("name", "dup"), ListOptions{}); err != nil || got.ID != "2" {
+		t.Fatalf("First with duplicates: %+v, %v", got, err)
+	}
+	if _, err := repo.FindOne(ctx, Eq("name", "dup"), ListOptions{}); !errors.Is(err, ErrMultipleMatches) {
+		t.Fatalf("expected ErrMultipleMatches, got %v", err)
+	}
+	if got, err := repo.FindOne(ctx, Eq("name", "one"), ListOptions{}); err != nil || got.ID != "1" {
+		t.Fatalf("FindOne: %+v, %v", got, err)
+	}
+}
+
+func TestRepositoryCountAndExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("fields") != "id" || q.Get("perPage") != "1" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		if q.Get("filter") == "status='none'" {
+			writeJSON(w, http.StatusOK, map[string]any{"items": []any{}, "totalItems": 0})
+			return
+		}
+		if q.Get("skipTotal") == "1" {
+			writeJSON(w, http.StatusOK, map[string]any{"items": []testRecord{{ID: "1"}}, "totalItems": -1})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": []testRecord{{ID: "1"}}, "page": 1, "perPage": 1, "totalItems": 42, "totalPages": 42})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+	ctx := context.Background()
+
+	count, err := repo.Count(ctx, Eq("status", "open"))
+	if err != nil || count != 42 {
+		t.Fatalf("Count: %d, %v", count, err)
+	}
+
+	exists, err := repo.Exists(ctx, Eq("status", "open"))
+	if err != nil || !exists {
+		t.Fatalf("Exists: %v, %v", exists, err)
+	}
+	exists, err = repo.Exists(ctx, Eq("status", "none"))
+	if err != nil || exists {
+		t.Fatalf("Exists for no match: %v, %v", exists, err)
+	}
+}
+
 func readBody(t *testing.T, r *http.Request) []byte {
 	t.Helper()
 	data, err := io.ReadAll(r.Body)
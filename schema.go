@@ -0,0 +1,354 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CollectionSchema is the definition of a collection as returned by /api/collections/{name}.
+type CollectionSchema struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Fields []SchemaField `json:"fields"`
+}
+
+// SchemaField is a single field definition. Which settings apply depends on Type.
+type SchemaField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	System   bool   `json:"system"`
+	Hidden   bool   `json:"hidden"`
+	// Min and Max are length limits for text fields (0 means unlimited) and
+	// value limits for number fields.
+	Min       *float64 `json:"min"`
+	Max       *float64 `json:"max"`
+	OnlyInt   bool     `json:"onlyInt"`
+	Pattern   string   `json:"pattern"`
+	Values    []string `json:"values"`
+	MaxSelect int      `json:"maxSelect"`
+	// CollectionID is the target collection of relation fields.
+	CollectionID string `json:"collectionId"`
+}
+
+// Field returns the field definition with name.
+func (s *CollectionSchema) Field(name string) (SchemaField, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+// FetchSchema loads the definition of collection. Reading collection
+// definitions requires superuser credentials.
+func FetchSchema(ctx context.Context, client AuthenticatedClient, collection string) (*CollectionSchema, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	collection = strings.TrimSpace(collection)
+	if collection == "" {
+		return nil, errors.New("collection is required")
+	}
+
+	resp, err := client.Do(ctx, http.MethodGet, "/api/collections/"+url.PathEscape(collection), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var schema CollectionSchema
+	if err := decodeJSONResponse(resp, &schema); err != nil {
+		return nil, fmt.Errorf("fetch schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// WithSchemaValidation validates records against the collection schema before
+// they are sent, so bad writes fail locally with a *ValidationError shaped
+// like the server's. The schema is fetched on first use and cached; see
+// Repository.RefreshSchema.
+func WithSchemaValidation() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.validateSchema = true
+	}
+}
+
+// schemaCache holds a repository's cached collection schema.
+type schemaCache struct {
+	mu     sync.Mutex
+	schema *CollectionSchema
+}
+
+// Schema returns the collection schema, fetching it on first use.
+func (r *Repository[T]) Schema(ctx context.Context) (*CollectionSchema, error) {
+	r.schema.mu.Lock()
+	defer r.schema.mu.Unlock()
+
+	if r.schema.schema != nil {
+		return r.schema.schema, nil
+	}
+	schema, err := FetchSchema(ctx, r.client, r.collection)
+	if err != nil {
+		return nil, err
+	}
+	r.schema.schema = schema
+	return schema, nil
+}
+
+// RefreshSchema re-fetches the cached collection schema, e.g. after a migration.
+func (r *Repository[T]) RefreshSchema(ctx context.Context) error {
+	schema, err := FetchSchema(ctx, r.client, r.collection)
+	if err != nil {
+		return err
+	}
+
+	r.schema.mu.Lock()
+	defer r.schema.mu.Unlock()
+	r.schema.schema = schema
+	return nil
+}
+
+// Validate checks record against the collection schema as a create would,
+// returning a *ValidationError listing every invalid field.
+func (r *Repository[T]) Validate(ctx context.Context, record T) error {
+	values, err := recordFields(record)
+	if err != nil {
+		return err
+	}
+	return r.validateValues(ctx, values, true)
+}
+
+// validatePayload validates an outgoing create or update body when schema
+// validation is enabled.
+func (r *Repository[T]) validatePayload(ctx context.Context, payload any, create bool) error {
+	if !r.opts.validateSchema {
+		return nil
+	}
+	values, err := recordFields(payload)
+	if err != nil {
+		return err
+	}
+	return r.validateValues(ctx, values, create)
+}
+
+func (r *Repository[T]) validateValues(ctx context.Context, values map[string]json.RawMessage, create bool) error {
+	schema, err := r.Schema(ctx)
+	if err != nil {
+		return err
+	}
+
+	message := "Failed to update record."
+	if create {
+		message = "Failed to create record."
+	}
+	if errs := validateRecord(schema, values, create); len(errs) > 0 {
+		return &ValidationError{Status: http.StatusBadRequest, Message: message, Fields: errs}
+	}
+	return nil
+}
+
+// validateRecord checks values against schema. On update only the fields
+// present in values are checked; modifier keys such as "views+" are skipped.
+func validateRecord(schema *CollectionSchema, values map[string]json.RawMessage, create bool) map[string]FieldError {
+	errs := make(map[string]FieldError)
+	for _, field := range schema.Fields {
+		raw, present := values[field.Name]
+		if !present && !create {
+			continue
+		}
+
+		var value any
+		if present {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				errs[field.Name] = FieldError{Code: "validation_invalid_value", Message: "Invalid value."}
+				continue
+			}
+		}
+		if fieldErr, ok := validateField(field, value); !ok {
+			errs[field.Name] = fieldErr
+		}
+	}
+	return errs
+}
+
+func validateField(field SchemaField, value any) (FieldError, bool) {
+	switch field.Type {
+	case "autodate", "file", "password":
+		// Managed by the server or sent outside the JSON body.
+		return FieldError{}, true
+	}
+	if field.Name == "id" && isBlank(value) {
+		// Generated by the server.
+		return FieldError{}, true
+	}
+
+	if isBlank(value) {
+		if field.Required {
+			return FieldError{Code: "validation_required", Message: "Cannot be blank."}, false
+		}
+		return FieldError{}, true
+	}
+
+	switch field.Type {
+	case "text", "editor", "email", "url":
+		return validateText(field, value)
+	case "number":
+		return validateNumber(field, value)
+	case "select":
+		return validateSelect(field, value)
+	case "relation":
+		return validateRelation(field, value)
+	}
+	return FieldError{}, true
+}
+
+func validateText(field SchemaField, value any) (FieldError, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return FieldError{}, true
+	}
+	length := float64(utf8.RuneCountInString(s))
+	if field.Min != nil && *field.Min > 0 && length < *field.Min {
+		return FieldError{
+			Code:    "validation_min_text_constraint",
+			Message: fmt.Sprintf("Must be at least %v character(s).", *field.Min),
+		}, false
+	}
+	if field.Max != nil && *field.Max > 0 && length > *field.Max {
+		return FieldError{
+			Code:    "validation_max_text_constraint",
+			Message: fmt.Sprintf("Must be no more than %v character(s).", *field.Max),
+		}, false
+	}
+	if field.Pattern != "" {
+		re, err := regexp.Compile(field.Pattern)
+		if err == nil && !re.MatchString(s) {
+			return FieldError{Code: "validation_invalid_format", Message: "Invalid value format."}, false
+		}
+	}
+	return FieldError{}, true
+}
+
+func validateNumber(field SchemaField, value any) (FieldError, bool) {
+	n, ok := numericValue(value)
+	if str, isString := value.(string); isString {
+		// PocketBase casts numeric strings submitted for number fields.
+		var err error
+		n, err = strconv.ParseFloat(str, 64)
+		ok = err == nil
+	}
+	if !ok {
+		return FieldError{Code: "validation_invalid_number", Message: "Must be a number."}, false
+	}
+	if field.OnlyInt && n != math.Trunc(n) {
+		return FieldError{Code: "validation_only_int_constraint", Message: "Decimal numbers are not allowed."}, false
+	}
+	if field.Min != nil && n < *field.Min {
+		return FieldError{
+			Code:    "validation_min_number_constraint",
+			Message: fmt.Sprintf("Must be larger than %v.", *field.Min),
+		}, false
+	}
+	if field.Max != nil && n > *field.Max {
+		return FieldError{
+			Code:    "validation_max_number_constraint",
+			Message: fmt.Sprintf("Must be less than %v.", *field.Max),
+		}, false
+	}
+	return FieldError{}, true
+}
+
+func validateSelect(field SchemaField, value any) (FieldError, bool) {
+	values := stringValues(value)
+	for _, v := range values {
+		if !slices.Contains(field.Values, v) {
+			return FieldError{Code: "validation_invalid_value", Message: fmt.Sprintf("Invalid value %s.", v)}, false
+		}
+	}
+	if field.MaxSelect > 0 && len(values) > field.MaxSelect {
+		return FieldError{
+			Code:    "validation_too_many_values",
+			Message: fmt.Sprintf("Select no more than %d.", field.MaxSelect),
+		}, false
+	}
+	return FieldError{}, true
+}
+
+// validateRelation only checks what holds for every collection: record ids
+// are non-empty strings. Their format depends on the id pattern of the target
+// collection, which is left to the server.
+func validateRelation(field SchemaField, value any) (FieldError, bool) {
+	ids, ok := relationIDs(value)
+	if !ok {
+		return FieldError{Code: "validation_invalid_relation", Message: "Invalid record id."}, false
+	}
+	if field.MaxSelect > 0 && len(ids) > field.MaxSelect {
+		return FieldError{
+			Code:    "validation_too_many_values",
+			Message: fmt.Sprintf("Select no more than %d.", field.MaxSelect),
+		}, false
+	}
+	return FieldError{}, true
+}
+
+// relationIDs returns the ids of a single or multiple relation value and
+// whether they are all non-empty strings.
+func relationIDs(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, v != ""
+	case []any:
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			id, ok := item.(string)
+			if !ok || id == "" {
+				return nil, false
+			}
+			ids = append(ids, id)
+		}
+		return ids, true
+	}
+	return nil, false
+}
+
+// stringValues returns the values of a single or multiple select or relation value.
+func stringValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, stringValue(item))
+		}
+		return out
+	}
+	return []string{stringValue(value)}
+}
+
+// isBlank reports whether PocketBase considers value empty for a required field.
+func isBlank(value any) bool {
+	switch v := value.(type) {
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case map[string]any:
+		return len(v) == 0
+	}
+	return isEmptyValue(value)
+}
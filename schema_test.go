@@ -0,0 +1,160 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const testPostsSchema = `{
+	"id": "pbc_posts",
+	"name": "posts",
+	"type": "base",
+	"fields": [
+		{"name":"id","type":"text","system":true,"required":true,"min":15,"max":15,"pattern":"^[a-z0-9]+$"},
+		{"name":"title","type":"text","required":true,"min":3,"max":10},
+		{"name":"slug","type":"text","pattern":"^[a-z-]+$"},
+		{"name":"rating","type":"number","min":1,"max":5,"onlyInt":true},
+		{"name":"status","type":"select","values":["draft","published"],"maxSelect":1},
+		{"name":"author","type":"relation","collectionId":"_pb_users_auth_","maxSelect":1},
+		{"name":"cover","type":"file","required":true},
+		{"name":"created","type":"autodate"}
+	]
+}`
+
+type schemaPost struct {
+	ID     string  `json:"id,omitempty"`
+	Title  string  `json:"title"`
+	Slug   string  `json:"slug,omitempty"`
+	Rating float64 `json:"rating,omitempty"`
+	Status string  `json:"status,omitempty"`
+	Author string  `json:"author,omitempty"`
+}
+
+func newSchemaServer(t *testing.T, schemaFetches, writes *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/collections/posts":
+			schemaFetches.Add(1)
+			_, _ = w.Write([]byte(testPostsSchema))
+		case r.Method == http.MethodPost || r.Method == http.MethodPatch:
+			writes.Add(1)
+			_, _ = w.Write([]byte(`{"id":"abc","title":"ok"}`))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestSchemaValidationRejectsInvalidCreate(t *testing.T) {
+	var fetches, writes atomic.Int32
+	server := newSchemaServer(t, &fetches, &writes)
+	defer server.Close()
+
+	repo := NewRepository[schemaPost](newTestClient(t, server), "posts", WithSchemaValidation())
+
+	_, err := repo.Create(context.Background(), schemaPost{
+		Title:  "a title that is too long",
+		Slug:   "Not A Slug",
+		Rating: 2.5,
+		Status: "archived",
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected error to match ErrBadRequest")
+	}
+
+	wantCodes := map[string]string{
+		"title":  "validation_max_text_constraint",
+		"slug":   "validation_invalid_format",
+		"rating": "validation_only_int_constraint",
+		"status": "validation_invalid_value",
+	}
+	if len(validationErr.Fields) != len(wantCodes) {
+		t.Fatalf("unexpected field errors: %+v", validationErr.Fields)
+	}
+	for field, code := range wantCodes {
+		if got := validationErr.Fields[field].Code; got != code {
+			t.Fatalf("%s: got code %q, want %q", field, got, code)
+		}
+	}
+	if writes.Load() != 0 {
+		t.Fatalf("expected no write request, got %d", writes.Load())
+	}
+
+	_, err = repo.Create(context.Background(), schemaPost{Title: "", Rating: 9})
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if validationErr.Fields["title"].Code != "validation_required" || validationErr.Fields["rating"].Code != "validation_max_number_constraint" {
+		t.Fatalf("unexpected field errors: %+v", validationErr.Fields)
+	}
+}
+
+func TestSchemaValidationAllowsValidWrites(t *testing.T) {
+	var fetches, writes atomic.Int32
+	server := newSchemaServer(t, &fetches, &writes)
+	defer server.Close()
+
+	repo := NewRepository[schemaPost](newTestClient(t, server), "posts", WithSchemaValidation())
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, schemaPost{Title: "hello", Slug: "hello-world", Rating: 4, Status: "draft", Author: "abc123"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Updates only check the fields that are sent.
+	if _, err := repo.UpdateFields(ctx, "abc", schemaPost{Status: "published"}, "status"); err != nil {
+		t.Fatalf("UpdateFields: %v", err)
+	}
+	if _, err := repo.Patch(ctx, "abc", Patch().Inc("rating", 1)); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if _, err := repo.UpdateFields(ctx, "abc", schemaPost{Title: ""}, "title"); err == nil {
+		t.Fatalf("expected blank required title to fail on update")
+	}
+
+	if writes.Load() != 3 {
+		t.Fatalf("expected 3 writes, got %d", writes.Load())
+	}
+	if fetches.Load() != 1 {
+		t.Fatalf("expected the schema to be fetched once, got %d", fetches.Load())
+	}
+
+	if err := repo.RefreshSchema(ctx); err != nil {
+		t.Fatalf("RefreshSchema: %v", err)
+	}
+	if fetches.Load() != 2 {
+		t.Fatalf("expected a refetch, got %d", fetches.Load())
+	}
+}
+
+func TestSchemaValidationLeavesRelationIDFormatToServer(t *testing.T) {
+	var fetches, writes atomic.Int32
+	server := newSchemaServer(t, &fetches, &writes)
+	defer server.Close()
+
+	repo := NewRepository[map[string]any](newTestClient(t, server), "posts", WithSchemaValidation())
+	ctx := context.Background()
+
+	// Target collections may use their own id pattern.
+	if err := repo.Validate(ctx, map[string]any{"title": "valid", "cover": "c.png", "author": "User-42_custom"}); err != nil {
+		t.Fatalf("expected a custom id to pass, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	err := repo.Validate(ctx, map[string]any{"title": "valid", "cover": "c.png", "author": []any{"a", "b"}})
+	if !errors.As(err, &validationErr) || validationErr.Fields["author"].Code != "validation_too_many_values" {
+		t.Fatalf("expected too many values, got %v", err)
+	}
+	err = repo.Validate(ctx, map[string]any{"title": "valid", "cover": "c.png", "author": []any{"a", 1}})
+	if !errors.As(err, &validationErr) || validationErr.Fields["author"].Code != "validation_invalid_relation" {
+		t.Fatalf("expected an invalid relation, got %v", err)
+	}
+}
@@ -0,0 +1,501 @@
+package pbclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const defaultImportBatchSize = 50
+
+// rawRecord holds a record with its fields left undecoded, so exports and
+// imports keep every column regardless of the repository's model type.
+type rawRecord = map[string]json.RawMessage
+
+// ExportOptions configures ExportNDJSON and ExportCSV.
+type ExportOptions struct {
+	Filter string
+	// Fields selects the exported fields; for CSV it also sets the column order.
+	// By default every field is exported.
+	Fields []string
+	// Sort is a single field to export by, prefixed with "-" for descending
+	// order; it defaults to "id". Pages are read with keyset pagination, so
+	// writes during the export do not skip or repeat records.
+	Sort    string
+	PerPage int
+	// Progress, if set, is called with the number of records exported so far after each page.
+	Progress func(exported int)
+}
+
+// ExportNDJSON writes every record matching opts to w as one JSON object per line
+// and returns the number of records written. Pages are fetched lazily, so the
+// collection is never held in memory.
+func (r *Repository[T]) ExportNDJSON(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	bw := bufio.NewWriter(w)
+	n, err := r.export(ctx, opts, func(record rawRecord) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("marshal record: %w", err)
+		}
+		_, err = bw.Write(append(data, '\n'))
+		return err
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, bw.Flush()
+}
+
+// ExportCSV writes every record matching opts to w as CSV with a header row and
+// returns the number of records written. Strings are written as is, null as an
+// empty cell and other values as JSON. Without opts.Fields the columns are the
+// fields of the first record, id first and the rest sorted.
+func (r *Repository[T]) ExportCSV(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	cw := csv.NewWriter(w)
+	columns := opts.Fields
+	header := false
+
+	n, err := r.export(ctx, opts, func(record rawRecord) error {
+		if len(columns) == 0 {
+			columns = recordColumns(record)
+		}
+		if !header {
+			header = true
+			if err := cw.Write(columns); err != nil {
+				return err
+			}
+		}
+
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvCell(record[column])
+		}
+		return cw.Write(row)
+	})
+	if err != nil {
+		return n, err
+	}
+	if !header && len(columns) > 0 {
+		if err := cw.Write(columns); err != nil {
+			return n, err
+		}
+	}
+	cw.Flush()
+	return n, cw.Error()
+}
+
+func (r *Repository[T]) export(ctx context.Context, opts ExportOptions, write func(rawRecord) error) (int, error) {
+	if r.client == nil {
+		return 0, errors.New("repository client is nil")
+	}
+	field, descending := strings.CutPrefix(strings.TrimSpace(opts.Sort), "-")
+	if field == "" {
+		field = "id"
+	}
+	if strings.Contains(field, ",") {
+		return 0, fmt.Errorf("export sort must be a single field, got %q", opts.Sort)
+	}
+
+	raw := NewRepository[rawRecord](r.client, r.collection)
+	cursor := CursorOptions{SortField: field, Descending: descending, Filter: opts.Filter, Fields: opts.Fields, PerPage: opts.PerPage}
+
+	n := 0
+	for page, err := range raw.CursorPages(ctx, cursor) {
+		if err != nil {
+			return n, err
+		}
+		for _, record := range page.Items {
+			if len(opts.Fields) > 0 {
+				// The cursor needs id and the sort field even when they are not exported.
+				maps.DeleteFunc(record, func(name string, _ json.RawMessage) bool {
+					return !slices.Contains(opts.Fields, name)
+				})
+			}
+			if err := write(record); err != nil {
+				return n, fmt.Errorf("write record %d: %w", n+1, err)
+			}
+			n++
+		}
+		if opts.Progress != nil {
+			opts.Progress(n)
+		}
+	}
+	return n, nil
+}
+
+func recordColumns(record rawRecord) []string {
+	columns := make([]string, 0, len(record))
+	for name := range record {
+		if name != "id" {
+			columns = append(columns, name)
+		}
+	}
+	slices.Sort(columns)
+	if _, ok := record["id"]; ok {
+		columns = append([]string{"id"}, columns...)
+	}
+	return columns
+}
+
+func csvCell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// ImportOptions configures ImportNDJSON and ImportCSV.
+type ImportOptions struct {
+	// Upsert updates records whose id already exists instead of failing; it implies KeepIDs.
+	Upsert bool
+	// KeepIDs creates records with the ids from the input. Otherwise new ids are generated.
+	KeepIDs bool
+	// BatchSize is the number of records per batch request; defaults to 50.
+	BatchSize int
+	// Progress, if set, is called with the number of rows processed so far after each batch.
+	Progress func(processed int)
+	// JSONFields names the CSV columns whose cells are JSON. When nil, ImportCSV
+	// reads the collection schema, which requires superuser credentials, and
+	// uses its json, select and relation fields. Set it, even to an empty
+	// slice, to import without reading the schema.
+	JSONFields []string
+}
+
+// ImportResult reports the outcome of an import.
+type ImportResult struct {
+	Rows     int
+	Imported int
+	Failed   int
+	// Errors lists the failed rows in input order.
+	Errors []RowError
+}
+
+// RowError describes a row that could not be imported. Row is 1-based and
+// counts data rows, excluding a CSV header.
+type RowError struct {
+	Row int
+	ID  string
+	Err error
+}
+
+func (e RowError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("row %d (id %s): %v", e.Row, e.ID, e.Err)
+	}
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportNDJSON reads one JSON object per line from rd and creates, or with
+// opts.Upsert upserts, the records in batches. Rows that fail to parse or to
+// write are reported in the result; the returned error is reserved for
+// failures that stop the import, such as a read error or a cancelled context.
+// System fields other than id are dropped.
+func (r *Repository[T]) ImportNDJSON(ctx context.Context, rd io.Reader, opts ImportOptions) (*ImportResult, error) {
+	dec := json.NewDecoder(rd)
+	return r.importRows(ctx, opts, func() (rawRecord, error) {
+		var record rawRecord
+		if err := dec.Decode(&record); err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				// The decoder cannot resynchronise after a syntax error.
+				return nil, fmt.Errorf("parse ndjson: %w", err)
+			}
+			return nil, &rowParseError{fmt.Errorf("decode record: %w", err)}
+		}
+		return record, nil
+	})
+}
+
+// ImportCSV reads records written by ExportCSV, or any CSV whose header names
+// the fields, and imports them like ImportNDJSON. Cells of json fields, and
+// JSON arrays in select and relation fields, are sent as JSON; see
+// ImportOptions.JSONFields. All other cells are sent as strings, which
+// PocketBase converts to the field type. Empty cells are sent as "".
+func (r *Repository[T]) ImportCSV(ctx context.Context, rd io.Reader, opts ImportOptions) (*ImportResult, error) {
+	cr := csv.NewReader(rd)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return &ImportResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	header = slices.Clone(header)
+
+	decoders, err := r.csvDecoders(ctx, header, opts.JSONFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.importRows(ctx, opts, func() (rawRecord, error) {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			if errors.Is(err, csv.ErrFieldCount) {
+				return nil, &rowParseError{err}
+			}
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+
+		record := make(rawRecord, len(header))
+		for i, column := range header {
+			record[column] = decoders[i](row[i])
+		}
+		return record, nil
+	})
+}
+
+// csvDecoders returns the cell decoder of each column in header, taking the
+// JSON columns from jsonFields or, when it is nil, from the collection schema.
+func (r *Repository[T]) csvDecoders(ctx context.Context, header, jsonFields []string) ([]func(string) json.RawMessage, error) {
+	decoders := make([]func(string) json.RawMessage, len(header))
+	for i := range decoders {
+		decoders[i] = csvString
+	}
+
+	if jsonFields != nil {
+		for i, column := range header {
+			if slices.Contains(jsonFields, column) {
+				decoders[i] = csvJSON
+			}
+		}
+		return decoders, nil
+	}
+
+	schema, err := r.Schema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read schema for csv import (set ImportOptions.JSONFields to skip it): %w", err)
+	}
+	for i, column := range header {
+		field, ok := schema.Field(column)
+		if !ok {
+			continue
+		}
+		switch field.Type {
+		case "json":
+			decoders[i] = csvJSON
+		case "select", "relation":
+			decoders[i] = csvList
+		}
+	}
+	return decoders, nil
+}
+
+// csvString sends a cell as a string.
+func csvString(cell string) json.RawMessage {
+	data, _ := json.Marshal(cell)
+	return data
+}
+
+// csvJSON sends a cell holding valid JSON as is. Other cells, such as JSON
+// strings that ExportCSV wrote unquoted, are sent as strings.
+func csvJSON(cell string) json.RawMessage {
+	trimmed := strings.TrimSpace(cell)
+	if trimmed != "" && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	return csvString(cell)
+}
+
+// csvList sends the JSON array of a multiple select or relation as is and
+// single values as strings.
+func csvList(cell string) json.RawMessage {
+	trimmed := strings.TrimSpace(cell)
+	if strings.HasPrefix(trimmed, "[") && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	return csvString(cell)
+}
+
+// rowParseError marks an input row that could not be parsed; the import continues with the next row.
+type rowParseError struct {
+	err error
+}
+
+func (e *rowParseError) Error() string { return e.err.Error() }
+
+type importRow struct {
+	row    int
+	id     string
+	record rawRecord
+}
+
+// importRows drains next until io.EOF and writes the records in batches. A
+// *rowParseError from next is recorded against the row; other errors stop the import.
+func (r *Repository[T]) importRows(ctx context.Context, opts ImportOptions, next func() (rawRecord, error)) (*ImportResult, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+	if opts.Upsert {
+		opts.KeepIDs = true
+	}
+
+	imp := &importer[T]{repo: r, opts: opts, useBatch: true, batchSize: opts.BatchSize, result: &ImportResult{}}
+	chunk := make([]importRow, 0, opts.BatchSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		err := imp.write(ctx, chunk)
+		chunk = chunk[:0]
+		if opts.Progress != nil {
+			opts.Progress(imp.result.Rows)
+		}
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return imp.result, err
+		}
+
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *rowParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return imp.result, err
+		}
+
+		imp.result.Rows++
+		row := imp.result.Rows
+		if parseErr != nil {
+			imp.fail(importRow{row: row}, parseErr.err)
+			continue
+		}
+
+		id := stringField(record, "id")
+		for name := range systemFields {
+			if name != "id" {
+				delete(record, name)
+			}
+		}
+		if !opts.KeepIDs {
+			delete(record, "id")
+		} else if id == "" {
+			imp.fail(importRow{row: row}, errors.New("id is required"))
+			continue
+		}
+
+		chunk = append(chunk, importRow{row: row, id: id, record: record})
+		if len(chunk) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return imp.result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return imp.result, err
+	}
+	return imp.result, nil
+}
+
+type importer[T any] struct {
+	repo      *Repository[T]
+	opts      ImportOptions
+	useBatch  bool
+	batchSize int
+	result    *ImportResult
+}
+
+// write imports a chunk through the batch API. When the batch fails because of
+// one of its rows, or the batch API is unavailable, the rows are written one by
+// one so that failures are attributed per row. Like bulk operations, it
+// shrinks the batches when the server limits their size.
+func (imp *importer[T]) write(ctx context.Context, rows []importRow) error {
+	if imp.useBatch && len(rows) > imp.batchSize {
+		for start := 0; start < len(rows); start += imp.batchSize {
+			if err := imp.write(ctx, rows[start:min(start+imp.batchSize, len(rows))]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if imp.useBatch {
+		batch := NewBatch(imp.repo.client)
+		for _, row := range rows {
+			if imp.opts.Upsert {
+				batch.Upsert(imp.repo.collection, row.record)
+			} else {
+				batch.Create(imp.repo.collection, row.record)
+			}
+		}
+		_, err := batch.Send(ctx)
+		if err == nil {
+			imp.result.Imported += len(rows)
+			return nil
+		}
+
+		var batchErr *BatchError
+		switch {
+		case errors.As(err, &batchErr):
+			// The chunk was rolled back; retry it row by row to isolate failures.
+		case isBatchTooLarge(err) && len(rows) > 1:
+			// The server's batch limit is lower than BatchSize; retry in smaller batches.
+			imp.batchSize = max(len(rows)/2, 1)
+			return imp.write(ctx, rows)
+		case errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) || isBatchTooLarge(err):
+			// Batch API disabled or unavailable on this server.
+			imp.useBatch = false
+		default:
+			return err
+		}
+	}
+
+	raw := NewRepository[rawRecord](imp.repo.client, imp.repo.collection)
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := imp.writeSingle(ctx, raw, row); err != nil {
+			imp.fail(row, err)
+			continue
+		}
+		imp.result.Imported++
+	}
+	return nil
+}
+
+func (imp *importer[T]) writeSingle(ctx context.Context, raw *Repository[rawRecord], row importRow) error {
+	if !imp.opts.Upsert {
+		_, err := raw.create(ctx, row.record)
+		return err
+	}
+
+	// Upsert without the batch API: update, and create when the record is missing.
+	_, err := raw.patch(ctx, row.id, row.record)
+	if errors.Is(err, ErrNotFound) {
+		_, err = raw.create(ctx, row.record)
+	}
+	return err
+}
+
+func (imp *importer[T]) fail(row importRow, err error) {
+	imp.result.Failed++
+	imp.result.Errors = append(imp.result.Errors, RowError{Row: row.row, ID: row.id, Err: err})
+}
@@ -0,0 +1,397 @@
+package pbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var afterIDPattern = regexp.MustCompile(`^id>'([^']*)'$`)
+
+// newExportServer serves the records sorted by id with keyset pagination.
+func newExportServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	records := []map[string]any{
+		{"id": "a1", "title": "first", "count": 1, "tags": []string{"x", "y"}, "note": nil},
+		{"id": "a2", "title": "second, with comma", "count": 2, "tags": []string{}, "note": "n"},
+		{"id": "a3", "title": "third", "count": 3, "tags": []string{"z"}, "note": ""},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("sort") != "id" {
+			t.Fatalf("expected default sort by id, got %q", q.Get("sort"))
+		}
+		if q.Get("page") != "1" {
+			t.Fatalf("expected keyset pagination, got page %q", q.Get("page"))
+		}
+		after := ""
+		if filter := q.Get("filter"); filter != "" {
+			m := afterIDPattern.FindStringSubmatch(filter)
+			if m == nil {
+				t.Fatalf("unexpected filter %q", filter)
+			}
+			after = m[1]
+		}
+		perPage := parseIntDefault(q.Get("perPage"), 30)
+
+		items := make([]map[string]any, 0)
+		for _, record := range records {
+			if record["id"].(string) > after && len(items) < perPage {
+				items = append(items, record)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": perPage})
+	}))
+}
+
+func TestRepositoryExportNDJSON(t *testing.T) {
+	server := newExportServer(t)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var progress []int
+	var buf bytes.Buffer
+	n, err := repo.ExportNDJSON(context.Background(), &buf, ExportOptions{PerPage: 2, Progress: func(done int) {
+		progress = append(progress, done)
+	}})
+	if err != nil {
+		t.Fatalf("ExportNDJSON: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 records, got %d", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	if lines[0] != `{"count":1,"id":"a1","note":null,"tags":["x","y"],"title":"first"}` {
+		t.Fatalf("unexpected first line: %s", lines[0])
+	}
+	if len(progress) != 2 || progress[1] != 3 {
+		t.Fatalf("unexpected progress: %v", progress)
+	}
+}
+
+func TestRepositoryExportCSV(t *testing.T) {
+	server := newExportServer(t)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var buf bytes.Buffer
+	if _, err := repo.ExportCSV(context.Background(), &buf, ExportOptions{}); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	want := "id,count,note,tags,title\n" +
+		"a1,1,,\"[\"\"x\"\",\"\"y\"\"]\",first\n" +
+		"a2,2,n,[],\"second, with comma\"\n" +
+		"a3,3,,\"[\"\"z\"\"]\",third\n"
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+type sentBatchRequest struct {
+	Method string         `json:"method"`
+	URL    string         `json:"url"`
+	Body   map[string]any `json:"body"`
+}
+
+func TestRepositoryImportNDJSONReportsRowErrors(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches int
+		created []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/api/batch" {
+			batches++
+			var payload struct {
+				Requests []sentBatchRequest `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			for i, req := range payload.Requests {
+				if req.Method != http.MethodPost {
+					t.Fatalf("expected create, got %s", req.Method)
+				}
+				if req.Body["title"] == "bad" {
+					writeJSON(w, http.StatusBadRequest, map[string]any{
+						"message": "Batch transaction failed.",
+						"data": map[string]any{"requests": map[string]any{
+							strconv.Itoa(i): map[string]any{
+								"code":     "batch_request_failed",
+								"message":  "Batch request failed.",
+								"response": map[string]any{"status": 400, "body": map[string]any{"message": "invalid title"}},
+							},
+						}},
+					})
+					return
+				}
+			}
+			results := make([]map[string]any, 0, len(payload.Requests))
+			for _, req := range payload.Requests {
+				created = append(created, req.Body)
+				results = append(results, map[string]any{"status": 200, "body": req.Body})
+			}
+			writeJSON(w, http.StatusOK, results)
+			return
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(readBody(t, r), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body["title"] == "bad" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"message": "invalid title"})
+			return
+		}
+		created = append(created, body)
+		writeJSON(w, http.StatusOK, body)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	input := strings.Join([]string{
+		`{"id":"old1","title":"one","created":"2024-01-01 00:00:00.000Z"}`,
+		`{"id":"old2","title":"bad"}`,
+		`["not","an","object"]`,
+		`{"id":"old3","title":"three"}`,
+		`{"id":"old4","title":"four"}`,
+	}, "\n")
+
+	res, err := repo.ImportNDJSON(context.Background(), strings.NewReader(input), ImportOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("ImportNDJSON: %v", err)
+	}
+	if res.Rows != 5 || res.Imported != 3 || res.Failed != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res.Errors[0].Row != 2 || !strings.Contains(res.Errors[0].Error(), "invalid title") {
+		t.Fatalf("unexpected first error: %v", res.Errors[0])
+	}
+	if res.Errors[1].Row != 3 {
+		t.Fatalf("unexpected second error: %v", res.Errors[1])
+	}
+	for _, body := range created {
+		if _, ok := body["id"]; ok {
+			t.Fatalf("expected ids to be dropped, got %v", body)
+		}
+		if _, ok := body["created"]; ok {
+			t.Fatalf("expected system fields to be dropped, got %v", body)
+		}
+	}
+	if batches != 2 {
+		t.Fatalf("expected 2 batch requests, got %d", batches)
+	}
+}
+
+// newCSVImportServer answers schema reads with fields and records the batch
+// requests it receives.
+func newCSVImportServer(t *testing.T, fields []SchemaField, requests *[]sentBatchRequest) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/collections/test" {
+			writeJSON(w, http.StatusOK, CollectionSchema{Name: "test", Fields: fields})
+			return
+		}
+
+		var payload struct {
+			Requests []sentBatchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		*requests = append(*requests, payload.Requests...)
+		results := make([]map[string]any, len(payload.Requests))
+		for i := range results {
+			results[i] = map[string]any{"status": 200, "body": map[string]any{}}
+		}
+		writeJSON(w, http.StatusOK, results)
+	}))
+}
+
+func TestRepositoryImportCSVUpserts(t *testing.T) {
+	var requests []sentBatchRequest
+	server := newCSVImportServer(t, []SchemaField{
+		{Name: "id", Type: "text"},
+		{Name: "title", Type: "text"},
+		{Name: "tags", Type: "select", MaxSelect: 5},
+	}, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	input := "id,title,tags\n" +
+		"a1,first,\"[\"\"x\"\"]\"\n" +
+		"a2,second\n" +
+		",no id,[]\n"
+
+	res, err := repo.ImportCSV(context.Background(), strings.NewReader(input), ImportOptions{Upsert: true})
+	if err != nil {
+		t.Fatalf("ImportCSV: %v", err)
+	}
+	if res.Rows != 3 || res.Imported != 1 || res.Failed != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(requests) != 1 || requests[0].Method != http.MethodPut {
+		t.Fatalf("unexpected requests: %+v", requests)
+	}
+	body := requests[0].Body
+	if body["id"] != "a1" || body["title"] != "first" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if tags, ok := body["tags"].([]any); !ok || len(tags) != 1 || tags[0] != "x" {
+		t.Fatalf("expected tags to be sent as JSON, got %v", body["tags"])
+	}
+}
+
+func TestRepositoryImportCSVDecodesOnlyJSONFields(t *testing.T) {
+	var requests []sentBatchRequest
+	server := newCSVImportServer(t, []SchemaField{
+		{Name: "title", Type: "text"},
+		{Name: "meta", Type: "json"},
+		{Name: "owner", Type: "relation", MaxSelect: 1},
+	}, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	input := "title,meta,owner,extra\n" +
+		"[1],\"{\"\"a\"\":1}\",u1,{}\n" +
+		"{},plain,u2,\n"
+
+	if _, err := repo.ImportCSV(context.Background(), strings.NewReader(input), ImportOptions{}); err != nil {
+		t.Fatalf("ImportCSV: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("unexpected requests: %+v", requests)
+	}
+	first, second := requests[0].Body, requests[1].Body
+	if first["title"] != "[1]" || second["title"] != "{}" || first["extra"] != "{}" {
+		t.Fatalf("expected text cells to stay strings, got %v and %v", first, second)
+	}
+	if meta, ok := first["meta"].(map[string]any); !ok || meta["a"] != float64(1) {
+		t.Fatalf("expected json field to be decoded, got %v", first["meta"])
+	}
+	if second["meta"] != "plain" || first["owner"] != "u1" {
+		t.Fatalf("unexpected values: %v and %v", first, second)
+	}
+}
+
+func TestRepositoryImportCSVWithExplicitJSONFields(t *testing.T) {
+	var requests []sentBatchRequest
+	server := newCSVImportServer(t, nil, &requests)
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	input := "title,meta\n[1],[1]\n"
+	opts := ImportOptions{JSONFields: []string{"meta"}}
+	if _, err := repo.ImportCSV(context.Background(), strings.NewReader(input), opts); err != nil {
+		t.Fatalf("ImportCSV: %v", err)
+	}
+	body := requests[0].Body
+	if body["title"] != "[1]" {
+		t.Fatalf("expected title to stay a string, got %v", body["title"])
+	}
+	if meta, ok := body["meta"].([]any); !ok || len(meta) != 1 {
+		t.Fatalf("expected meta to be sent as JSON, got %v", body["meta"])
+	}
+}
+
+func TestRepositoryExportWithFieldsAndDescendingSort(t *testing.T) {
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		filters = append(filters, q.Get("filter"))
+		if q.Get("sort") != "-created,-id" || q.Get("fields") != "title,id,created" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		items := []map[string]any{}
+		switch len(filters) {
+		case 1:
+			items = append(items, map[string]any{"id": "b", "created": "2024-01-02 00:00:00.000Z", "title": "new"})
+		case 2:
+			items = append(items, map[string]any{"id": "a", "created": "2024-01-01 00:00:00.000Z", "title": "old"})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 1})
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var buf bytes.Buffer
+	opts := ExportOptions{Fields: []string{"title"}, Sort: "-created", PerPage: 1}
+	n, err := repo.ExportNDJSON(context.Background(), &buf, opts)
+	if err != nil {
+		t.Fatalf("ExportNDJSON: %v", err)
+	}
+	if n != 2 || buf.String() != "{\"title\":\"new\"}\n{\"title\":\"old\"}\n" {
+		t.Fatalf("unexpected export (%d): %q", n, buf.String())
+	}
+	want := "(created<'2024-01-02 00:00:00.000Z' || (created='2024-01-02 00:00:00.000Z' && id<'b'))"
+	if len(filters) < 2 || filters[1] != want {
+		t.Fatalf("unexpected filters: %q", filters)
+	}
+}
+
+func TestRepositoryImportShrinksBatchesToServerLimit(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Requests []sentBatchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(payload.Requests) > 2 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"message": "Invalid batch request data.",
+				"data": map[string]any{"requests": map[string]any{
+					"code": "validation_length_too_long", "message": "The length must be no more than 2.",
+				}},
+			})
+			return
+		}
+		sizes = append(sizes, len(payload.Requests))
+		results := make([]map[string]any, len(payload.Requests))
+		for i := range results {
+			results[i] = map[string]any{"status": 200, "body": map[string]any{}}
+		}
+		writeJSON(w, http.StatusOK, results)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	var lines []string
+	for i := range 7 {
+		lines = append(lines, fmt.Sprintf(`{"title":"t%d"}`, i))
+	}
+	res, err := repo.ImportNDJSON(context.Background(), strings.NewReader(strings.Join(lines, "\n")), ImportOptions{BatchSize: 5})
+	if err != nil {
+		t.Fatalf("ImportNDJSON: %v", err)
+	}
+	if res.Rows != 7 || res.Imported != 7 || res.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if fmt.Sprint(sizes) != "[2 2 1 2]" {
+		t.Fatalf("unexpected batch sizes: %v", sizes)
+	}
+}
@@ -0,0 +1,169 @@
+package pbclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BaseRecord holds the system fields shared by every PocketBase record.
+// Embed it in models instead of re-declaring them:
+//
+//	type Todo struct {
+//		pbclient.BaseRecord
+//		Title string `json:"title"`
+//	}
+type BaseRecord struct {
+	ID             string   `json:"id,omitempty"`
+	CollectionID   string   `json:"collectionId,omitempty"`
+	CollectionName string   `json:"collectionName,omitempty"`
+	Created        DateTime `json:"created,omitzero"`
+	Updated        DateTime `json:"updated,omitzero"`
+}
+
+// dateTimeLayout is the format PocketBase uses for date and autodate fields.
+const dateTimeLayout = "2006-01-02 15:04:05.000Z07:00"
+
+// DateTime handles PocketBase's datetime format, which uses a space instead of
+// the RFC 3339 "T". Empty strings and null decode to the zero time, and the
+// zero time encodes as an empty string, which PocketBase treats as unset.
+type DateTime struct {
+	time.Time
+}
+
+// NewDateTime wraps t as a DateTime.
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{Time: t}
+}
+
+// String formats the time as PocketBase does, in UTC, or returns "" for the zero time.
+// The result can be quoted into filters, e.g. Gt("created", "'"+dt.String()+"'").
+func (t DateTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(dateTimeLayout)
+}
+
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *DateTime) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), "\"")
+	if str == "" || str == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, dateTimeLayout, "2006-01-02 15:04:05Z07:00"} {
+		if parsed, err := time.Parse(layout, str); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("parse time: %s", str)
+}
+
+// JSONField holds the value of a PocketBase json field. Valid is false when the
+// field is null. Values stored as JSON-encoded strings are decoded as well.
+type JSONField[T any] struct {
+	Value T
+	Valid bool
+}
+
+// NewJSONField returns a valid JSONField holding value.
+func NewJSONField[T any](value T) JSONField[T] {
+	return JSONField[T]{Value: value, Valid: true}
+}
+
+func (f JSONField[T]) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Value)
+}
+
+func (f *JSONField[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	f.Value = zero
+	f.Valid = false
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	err := json.Unmarshal(data, &f.Value)
+	if err != nil {
+		var encoded string
+		if json.Unmarshal(data, &encoded) != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(encoded), &f.Value); err != nil {
+			return fmt.Errorf("decode json field: %w", err)
+		}
+	}
+	f.Valid = true
+	return nil
+}
+
+// MultiSelect holds the values of a select field. PocketBase returns a plain
+// string for single selects and an array otherwise; both decode into the slice.
+type MultiSelect []string
+
+func (s MultiSelect) MarshalJSON() ([]byte, error) {
+	return marshalStringList(s)
+}
+
+func (s *MultiSelect) UnmarshalJSON(data []byte) error {
+	return unmarshalStringList(data, (*[]string)(s))
+}
+
+// RelationIDs holds the record ids of a relation field, whether PocketBase
+// returns them as a single string or as an array.
+type RelationIDs []string
+
+func (r RelationIDs) MarshalJSON() ([]byte, error) {
+	return marshalStringList(r)
+}
+
+func (r *RelationIDs) UnmarshalJSON(data []byte) error {
+	return unmarshalStringList(data, (*[]string)(r))
+}
+
+func marshalStringList(values []string) ([]byte, error) {
+	if values == nil {
+		values = []string{}
+	}
+	return json.Marshal(values)
+}
+
+func unmarshalStringList(data []byte, dst *[]string) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*dst = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var single string
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		if single == "" {
+			*dst = nil
+			return nil
+		}
+		*dst = []string{single}
+		return nil
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// GeoPoint holds the value of a geoPoint field.
+type GeoPoint struct {
+	Lon float64 `json:"lon"`
+	Lat float64 `json:"lat"`
+}
@@ -0,0 +1,111 @@
+package pbclient
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	var dt DateTime
+	if err := json.Unmarshal([]byte(`"2024-03-05 10:20:30.123Z"`), &dt); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := time.Date(2024, 3, 5, 10, 20, 30, 123000000, time.UTC)
+	if !dt.Equal(want) {
+		t.Fatalf("got %v, want %v", dt.Time, want)
+	}
+
+	data, err := json.Marshal(dt)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `"2024-03-05 10:20:30.123Z"` {
+		t.Fatalf("unexpected encoding %s", data)
+	}
+
+	for _, raw := range []string{`""`, `null`} {
+		var empty DateTime
+		if err := json.Unmarshal([]byte(raw), &empty); err != nil || !empty.IsZero() {
+			t.Fatalf("expected zero time for %s, got %v (%v)", raw, empty, err)
+		}
+	}
+	if data, _ := json.Marshal(DateTime{}); string(data) != `""` {
+		t.Fatalf("zero time should encode as empty string, got %s", data)
+	}
+}
+
+func TestBaseRecordEmbedding(t *testing.T) {
+	type todo struct {
+		BaseRecord
+		Title string `json:"title"`
+	}
+
+	var got todo
+	raw := `{"id":"t1","collectionId":"c1","collectionName":"todos","created":"2024-01-01 00:00:00.000Z","updated":"","title":"x"}`
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.ID != "t1" || got.CollectionName != "todos" || got.Created.IsZero() || !got.Updated.IsZero() {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	data, err := json.Marshal(todo{Title: "new"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"title":"new"}` {
+		t.Fatalf("system fields should be omitted when empty, got %s", data)
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	type payload struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	var direct JSONField[payload]
+	if err := json.Unmarshal([]byte(`{"enabled":true}`), &direct); err != nil || !direct.Valid || !direct.Value.Enabled {
+		t.Fatalf("direct: %+v, %v", direct, err)
+	}
+
+	var encoded JSONField[payload]
+	if err := json.Unmarshal([]byte(`"{\"enabled\":true}"`), &encoded); err != nil || !encoded.Value.Enabled {
+		t.Fatalf("encoded string: %+v, %v", encoded, err)
+	}
+
+	var null JSONField[payload]
+	if err := json.Unmarshal([]byte(`null`), &null); err != nil || null.Valid {
+		t.Fatalf("null: %+v, %v", null, err)
+	}
+	if data, _ := json.Marshal(null); string(data) != "null" {
+		t.Fatalf("invalid field should encode as null, got %s", data)
+	}
+}
+
+func TestStringListTypes(t *testing.T) {
+	var single MultiSelect
+	if err := json.Unmarshal([]byte(`"a"`), &single); err != nil || len(single) != 1 || single[0] != "a" {
+		t.Fatalf("single: %v, %v", single, err)
+	}
+
+	var ids RelationIDs
+	if err := json.Unmarshal([]byte(`["r1","r2"]`), &ids); err != nil || len(ids) != 2 {
+		t.Fatalf("array: %v, %v", ids, err)
+	}
+
+	var empty RelationIDs
+	if err := json.Unmarshal([]byte(`""`), &empty); err != nil || len(empty) != 0 {
+		t.Fatalf("empty: %v, %v", empty, err)
+	}
+	if data, _ := json.Marshal(empty); string(data) != "[]" {
+		t.Fatalf("nil list should encode as [], got %s", data)
+	}
+}
+
+func TestGeoPoint(t *testing.T) {
+	var p GeoPoint
+	if err := json.Unmarshal([]byte(`{"lon":13.4,"lat":52.5}`), &p); err != nil || p.Lon != 13.4 || p.Lat != 52.5 {
+		t.Fatalf("GeoPoint: %+v, %v", p, err)
+	}
+}
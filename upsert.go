@@ -0,0 +1,94 @@
+package pbclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Upsert creates record or, when a record with the same id already exists, updates it.
+// The record must carry an "id"; the write is a single-operation batch upsert, so the
+// batch API has to be enabled in PocketBase. Because the server decides whether
+// the write is a create or an update, repository hooks do not run.
+func (r *Repository[T]) Upsert(ctx context.Context, record T) (*T, error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if r.collection == "" {
+		return nil, errors.New("collection is required")
+	}
+
+	values, err := recordFields(record)
+	if err != nil {
+		return nil, err
+	}
+	if stringField(values, "id") == "" {
+		return nil, errors.New("id is required for upsert")
+	}
+
+	batch := NewBatch(r.client)
+	r.BatchUpsert(batch, record)
+	results, err := batch.Send(ctx)
+	if err != nil {
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			return nil, batchErr.Err
+		}
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, errors.New("unexpected batch response")
+	}
+
+	var out T
+	if err := results[0].Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpsertBy updates the record matching filter, typically a natural key, or creates
+// record when nothing matches. If the create loses a race against a concurrent
+// writer and fails on a unique constraint, it is retried as an update. The
+// create and update hooks run for whichever write is made. An empty filter
+// would match any record, so it is rejected with ErrEmptyFilter.
+func (r *Repository[T]) UpsertBy(ctx context.Context, filter string, record T) (*T, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, ErrEmptyFilter
+	}
+
+	id, err := r.lookupID(ctx, filter)
+	if err == nil {
+		return r.Update(ctx, id, record)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	created, createErr := r.Create(ctx, record)
+	if createErr == nil {
+		return created, nil
+	}
+	if !errors.Is(createErr, ErrBadRequest) {
+		return nil, createErr
+	}
+
+	// A unique index violation surfaces as a 400; check whether a concurrent writer won.
+	id, err = r.lookupID(ctx, filter)
+	if err != nil {
+		return nil, createErr
+	}
+	return r.Update(ctx, id, record)
+}
+
+// lookupID returns the id of the first record matching filter.
+func (r *Repository[T]) lookupID(ctx context.Context, filter string) (string, error) {
+	ids := NewRepository[struct {
+		ID string `json:"id"`
+	}](r.client, r.collection)
+	item, err := ids.First(ctx, filter, ListOptions{Fields: []string{"id"}})
+	if err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
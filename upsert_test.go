@@ -0,0 +1,100 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryUpsertUsesBatchPut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/batch" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		var payload struct {
+			Requests []batchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(payload.Requests) != 1 || payload.Requests[0].Method != http.MethodPut {
+			t.Fatalf("unexpected batch: %+v", payload.Requests)
+		}
+		_, _ = w.Write([]byte(`[{"status":200,"body":{"id":"known1234567890","name":"demo"}}]`))
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	got, err := repo.Upsert(context.Background(), testRecord{ID: "known1234567890", Name: "demo"})
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if got.ID != "known1234567890" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	if _, err := repo.Upsert(context.Background(), testRecord{Name: "no id"}); err == nil {
+		t.Fatalf("expected error without id")
+	}
+}
+
+func TestRepositoryUpsertByRetriesAsUpdateAfterConflict(t *testing.T) {
+	var lookups int
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method)
+		switch r.Method {
+		case http.MethodGet:
+			lookups++
+			items := []testRecord{}
+			if lookups > 1 {
+				items = append(items, testRecord{ID: "winner"})
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"items": items})
+		case http.MethodPost:
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"message": "Failed to create record.",
+				"data":    map[string]any{"name": map[string]string{"code": "validation_not_unique", "message": "Value must be unique."}},
+			})
+		case http.MethodPatch:
+			if !strings.HasSuffix(r.URL.Path, "/winner") {
+				t.Fatalf("unexpected update path: %s", r.URL.Path)
+			}
+			_, _ = w.Write([]byte(`{"id":"winner","name":"demo"}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	got, err := repo.UpsertBy(context.Background(), Eq("name", "demo"), testRecord{Name: "demo"})
+	if err != nil {
+		t.Fatalf("UpsertBy: %v", err)
+	}
+	if got.ID != "winner" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+	if strings.Join(calls, ",") != "GET,POST,GET,PATCH" {
+		t.Fatalf("unexpected call sequence: %v", calls)
+	}
+}
+
+func TestRepositoryUpsertByRejectsEmptyFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	repo := NewRepository[testRecord](newTestClient(t, server), "test")
+
+	for _, filter := range []string{"", "  "} {
+		if _, err := repo.UpsertBy(context.Background(), filter, testRecord{Name: "demo"}); !errors.Is(err, ErrEmptyFilter) {
+			t.Fatalf("filter %q: expected ErrEmptyFilter, got %v", filter, err)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package pbclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	realtimePath             = "/api/realtime"
+	defaultWatchReconnect    = time.Second
+	maxWatchReconnect        = 30 * time.Second
+	maxRealtimeMessageLength = 16 << 20
+)
+
+// Event actions reported by Watch.
+const (
+	EventCreate = "create"
+	EventUpdate = "update"
+	EventDelete = "delete"
+)
+
+// Event is a change to a watched record.
+type Event[T any] struct {
+	Action string
+	Record T
+}
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Filter and Expand are applied by the server to the subscription.
+	Filter string
+	Expand []string
+	// CatchUp lists records whose "updated" timestamp is at or after the last
+	// one seen whenever the connection is re-established, and emits them as
+	// create or update events. Records already emitted with the same
+	// timestamp are skipped. Deletes made while disconnected cannot be
+	// recovered this way.
+	CatchUp bool
+	// Since seeds CatchUp, so that changes since a checkpoint, including
+	// those made at the checkpoint itself, are emitted right after the first
+	// connection as well.
+	Since DateTime
+	// ReconnectDelay is the initial delay between reconnect attempts, doubled
+	// up to 30s while they keep failing; defaults to 1s.
+	ReconnectDelay time.Duration
+	// Buffer is the capacity of the returned channel.
+	Buffer int
+	// OnError, if set, is called with errors that cause a reconnect.
+	OnError func(error)
+}
+
+// Watch subscribes to realtime changes of the collection and returns a channel
+// of typed events. topic is "*" for every record or a record id. The
+// connection is re-established and the subscription renewed after failures;
+// the channel is closed when ctx is done.
+//
+// Watch returns an error if the first connection cannot be established.
+// Reading records may require the same access rules as List and Get.
+func (r *Repository[T]) Watch(ctx context.Context, topic string, opts WatchOptions) (<-chan Event[T], error) {
+	if r.client == nil {
+		return nil, errors.New("repository client is nil")
+	}
+	if r.collection == "" {
+		return nil, errors.New("collection is required")
+	}
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = defaultWatchReconnect
+	}
+
+	w := &watcher[T]{
+		repo:     r,
+		topic:    topic,
+		opts:     opts,
+		out:      make(chan Event[T], max(opts.Buffer, 0)),
+		lastSeen: opts.Since.String(),
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	go w.run(ctx, conn)
+	return w.out, nil
+}
+
+type watcher[T any] struct {
+	repo     *Repository[T]
+	topic    string
+	opts     WatchOptions
+	out      chan Event[T]
+	lastSeen string
+	// seenIDs holds the raw ids of the records emitted with the lastSeen
+	// timestamp, which the inclusive catch-up returns again.
+	seenIDs map[string]bool
+}
+
+type realtimeConn struct {
+	body   io.ReadCloser
+	events *sseReader
+}
+
+func (w *watcher[T]) run(ctx context.Context, conn *realtimeConn) {
+	defer close(w.out)
+
+	delay := w.opts.ReconnectDelay
+	for {
+		err := w.consume(ctx, conn)
+		conn.body.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		w.reportError(err)
+
+		for {
+			if !sleepContext(ctx, delay) {
+				return
+			}
+			conn, err = w.connect(ctx)
+			if err == nil {
+				delay = w.opts.ReconnectDelay
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			w.reportError(err)
+			delay = min(delay*2, maxWatchReconnect)
+		}
+	}
+}
+
+// connect opens the event stream, subscribes to the topic and, if enabled, catches up.
+func (w *watcher[T]) connect(ctx context.Context) (*realtimeConn, error) {
+	resp, err := w.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, mapHTTPError(resp.StatusCode, body)
+	}
+
+	conn := &realtimeConn{body: resp.Body, events: newSSEReader(resp.Body)}
+	if err := w.subscribe(ctx, conn); err != nil {
+		conn.body.Close()
+		return nil, err
+	}
+	if w.opts.CatchUp {
+		if err := w.catchUp(ctx); err != nil {
+			conn.body.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (w *watcher[T]) open(ctx context.Context) (*http.Response, error) {
+	if s, ok := w.repo.client.(streamer); ok {
+		return s.stream(ctx, realtimePath, http.Header{"Accept": {"text/event-stream"}})
+	}
+	// Other clients may apply a timeout; the stream is then simply reconnected.
+	return w.repo.client.Do(ctx, http.MethodGet, realtimePath, nil)
+}
+
+// subscribe waits for the PB_CONNECT event and registers the subscription for its client id.
+func (w *watcher[T]) subscribe(ctx context.Context, conn *realtimeConn) error {
+	event, err := conn.events.next()
+	if err != nil {
+		return fmt.Errorf("realtime connect: %w", err)
+	}
+	if event.name != "PB_CONNECT" {
+		return fmt.Errorf("realtime connect: unexpected event %q", event.name)
+	}
+	var connect struct {
+		ClientID string `json:"clientId"`
+	}
+	if err := json.Unmarshal(event.data, &connect); err != nil || connect.ClientID == "" {
+		connect.ClientID = event.id
+	}
+	if connect.ClientID == "" {
+		return errors.New("realtime connect: missing client id")
+	}
+
+	subscription, err := w.subscription()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]any{
+		"clientId":      connect.ClientID,
+		"subscriptions": []string{subscription},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal subscription: %w", err)
+	}
+
+	resp, err := w.repo.client.Do(ctx, http.MethodPost, realtimePath, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return mapHTTPError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+// subscription returns the topic key, e.g. "posts/*?options={...}".
+func (w *watcher[T]) subscription() (string, error) {
+	key := w.repo.collection + "/" + w.topic
+
+	query := make(map[string]string)
+	if w.opts.Filter != "" {
+		query["filter"] = w.opts.Filter
+	}
+	if len(w.opts.Expand) > 0 {
+		query["expand"] = strings.Join(w.opts.Expand, ",")
+	}
+	if len(query) == 0 {
+		return key, nil
+	}
+
+	options, err := json.Marshal(map[string]any{"query": query})
+	if err != nil {
+		return "", fmt.Errorf("marshal subscription options: %w", err)
+	}
+	return key + "?options=" + url.QueryEscape(string(options)), nil
+}
+
+// consume emits the events of conn until the stream ends.
+func (w *watcher[T]) consume(ctx context.Context, conn *realtimeConn) error {
+	for {
+		event, err := conn.events.next()
+		if err != nil {
+			return err
+		}
+		if event.name == "PB_CONNECT" || len(event.data) == 0 {
+			continue
+		}
+
+		var msg struct {
+			Action string          `json:"action"`
+			Record json.RawMessage `json:"record"`
+		}
+		if err := json.Unmarshal(event.data, &msg); err != nil {
+			w.reportError(fmt.Errorf("decode realtime event: %w", err))
+			continue
+		}
+		if !w.emit(ctx, msg.Action, msg.Record) {
+			return ctx.Err()
+		}
+	}
+}
+
+// catchUp emits the records updated at or after the last seen timestamp.
+// PocketBase timestamps have millisecond precision, so several records may
+// share one; the inclusive filter keeps the ones missed at that instant and
+// the records already emitted are skipped. Without a checkpoint it only
+// records the newest timestamp in the collection, so that changes made during
+// a later disconnect are not lost.
+func (w *watcher[T]) catchUp(ctx context.Context) error {
+	if w.lastSeen == "" {
+		return w.seedLastSeen(ctx)
+	}
+
+	filter := And(Gte("updated", quoteFilterValue(w.lastSeen)), w.opts.Filter)
+	if w.topic != "*" {
+		filter = And(Eq("id", w.topic), filter)
+	}
+
+	raw := NewRepository[json.RawMessage](w.repo.client, w.repo.collection)
+	cursor := CursorOptions{SortField: "updated", Filter: filter, Expand: w.opts.Expand}
+	for page, err := range raw.CursorPages(ctx, cursor) {
+		if err != nil {
+			return fmt.Errorf("catch up: %w", err)
+		}
+
+		for _, data := range page.Items {
+			var times struct {
+				ID      json.RawMessage `json:"id"`
+				Created string          `json:"created"`
+				Updated string          `json:"updated"`
+			}
+			_ = json.Unmarshal(data, &times)
+			if times.Updated == w.lastSeen && w.seenIDs[string(times.ID)] {
+				continue
+			}
+			action := EventUpdate
+			if times.Created != "" && times.Created == times.Updated {
+				action = EventCreate
+			}
+			if !w.emit(ctx, action, data) {
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// seedLastSeen starts the checkpoint at the newest timestamp in the
+// collection and marks the records updated at that instant as seen.
+func (w *watcher[T]) seedLastSeen(ctx context.Context) error {
+	raw := NewRepository[struct {
+		ID      json.RawMessage `json:"id"`
+		Updated string          `json:"updated"`
+	}](w.repo.client, w.repo.collection)
+
+	w.lastSeen = ""
+	w.seenIDs = make(map[string]bool)
+	for latest, err := range raw.All(ctx, ListOptions{Sort: "-updated", Fields: []string{"id", "updated"}}) {
+		if err != nil {
+			return fmt.Errorf("catch up: %w", err)
+		}
+		if w.lastSeen == "" {
+			w.lastSeen = latest.Updated
+		}
+		if latest.Updated != w.lastSeen {
+			break
+		}
+		w.seenIDs[string(latest.ID)] = true
+	}
+	if w.lastSeen == "" {
+		// An empty collection: everything created later is newer than this.
+		w.lastSeen = "0001-01-01 00:00:00.000Z"
+	}
+	return nil
+}
+
+// emit decodes record and sends the event, tracking the newest updated timestamp.
+// It returns false when ctx is done.
+func (w *watcher[T]) emit(ctx context.Context, action string, record json.RawMessage) bool {
+	var event Event[T]
+	event.Action = action
+	if err := json.Unmarshal(record, &event.Record); err != nil {
+		w.reportError(fmt.Errorf("decode realtime record: %w", err))
+		return true
+	}
+
+	var times struct {
+		ID      json.RawMessage `json:"id"`
+		Updated string          `json:"updated"`
+	}
+	if json.Unmarshal(record, &times) == nil && times.Updated >= w.lastSeen {
+		if times.Updated > w.lastSeen || w.seenIDs == nil {
+			w.lastSeen = times.Updated
+			w.seenIDs = make(map[string]bool)
+		}
+		w.seenIDs[string(times.ID)] = true
+	}
+
+	select {
+	case w.out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *watcher[T]) reportError(err error) {
+	if err != nil && w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+type sseEvent struct {
+	id   string
+	name string
+	data []byte
+}
+
+// sseReader parses a text/event-stream.
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRealtimeMessageLength)
+	return &sseReader{scanner: scanner}
+}
+
+// next returns the next event, or io.EOF when the stream ends.
+func (s *sseReader) next() (sseEvent, error) {
+	var event sseEvent
+	var data [][]byte
+	hasFields := false
+
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if !hasFields {
+				continue
+			}
+			event.data = bytes.Join(data, []byte("\n"))
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		hasFields = true
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.name = value
+		case "data":
+			data = append(data, []byte(value))
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return sseEvent{}, err
+	}
+	return sseEvent{}, io.EOF
+}
@@ -0,0 +1,154 @@
+package pbclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type watchRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Updated string `json:"updated"`
+}
+
+func writeSSE(w http.ResponseWriter, name, id string, data any) {
+	payload, _ := json.Marshal(data)
+	if id != "" {
+		fmt.Fprintf(w, "id:%s\n", id)
+	}
+	fmt.Fprintf(w, "event:%s\ndata:%s\n\n", name, payload)
+	w.(http.Flusher).Flush()
+}
+
+func TestRepositoryWatchReconnectsAndCatchesUp(t *testing.T) {
+	var (
+		connections   atomic.Int32
+		mu            sync.Mutex
+		subscriptions []string
+		filters       []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/realtime":
+			n := connections.Add(1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			clientID := fmt.Sprintf("client%d", n)
+			writeSSE(w, "PB_CONNECT", clientID, map[string]string{"clientId": clientID})
+
+			// Give the client time to subscribe before sending changes.
+			time.Sleep(20 * time.Millisecond)
+			if n == 1 {
+				writeSSE(w, "posts/*", "", map[string]any{
+					"action": "create",
+					"record": watchRecord{ID: "r1", Name: "first", Updated: "2024-01-01 10:00:00.000Z"},
+				})
+				return // drop the connection
+			}
+			writeSSE(w, "posts/*", "", map[string]any{
+				"action": "update",
+				"record": watchRecord{ID: "r3", Name: "third", Updated: "2024-01-01 10:05:00.000Z"},
+			})
+			<-r.Context().Done()
+
+		case r.Method == http.MethodPost && r.URL.Path == "/api/realtime":
+			var payload struct {
+				ClientID      string   `json:"clientId"`
+				Subscriptions []string `json:"subscriptions"`
+			}
+			_ = json.Unmarshal(readBody(t, r), &payload)
+			mu.Lock()
+			subscriptions = append(subscriptions, payload.ClientID+" "+strings.Join(payload.Subscriptions, ","))
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+
+		case r.URL.Path == "/api/collections/posts/records":
+			q := r.URL.Query()
+			mu.Lock()
+			filters = append(filters, q.Get("sort")+" "+q.Get("filter"))
+			mu.Unlock()
+			if q.Get("sort") == "-updated" {
+				items := []watchRecord{{ID: "r0", Updated: "2024-01-01 09:00:00.000Z"}}
+				writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": 1, "perPage": 200})
+				return
+			}
+			// r1 is returned again by the inclusive filter and must not be re-emitted.
+			writeJSON(w, http.StatusOK, map[string]any{
+				"items": []map[string]any{
+					{"id": "r1", "name": "first", "created": "2024-01-01 10:00:00.000Z", "updated": "2024-01-01 10:00:00.000Z"},
+					{"id": "r2", "name": "second", "created": "2024-01-01 08:00:00.000Z", "updated": "2024-01-01 10:01:00.000Z"},
+				},
+				"page":    1,
+				"perPage": 200,
+			})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewRepository[watchRecord](newTestClient(t, server), "posts")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := repo.Watch(ctx, "*", WatchOptions{
+		Filter:         Eq("status", "live"),
+		CatchUp:        true,
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	var got []string
+	for event := range events {
+		got = append(got, event.Action+":"+event.Record.ID)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+
+	want := "create:r1,update:r2,update:r3"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got events %v, want %s", got, want)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(subscriptions) != 2 || !strings.HasPrefix(subscriptions[1], "client2 posts/*?options=") {
+		t.Fatalf("unexpected subscriptions: %v", subscriptions)
+	}
+	if !strings.Contains(subscriptions[0], "status") {
+		t.Fatalf("expected the filter in the subscription options, got %q", subscriptions[0])
+	}
+	if len(filters) != 2 || filters[1] != "updated,id (updated>='2024-01-01 10:00:00.000Z' && status='live')" {
+		t.Fatalf("unexpected catch-up queries: %v", filters)
+	}
+}
+
+func TestSSEReaderParsesEvents(t *testing.T) {
+	stream := ": comment\n\nid:1\nevent:PB_CONNECT\ndata:{\"clientId\":\"1\"}\n\nevent: posts/*\ndata: line1\ndata: line2\n\n"
+	reader := newSSEReader(strings.NewReader(stream))
+
+	first, err := reader.next()
+	if err != nil || first.id != "1" || first.name != "PB_CONNECT" || string(first.data) != `{"clientId":"1"}` {
+		t.Fatalf("unexpected first event: %+v, %v", first, err)
+	}
+	second, err := reader.next()
+	if err != nil || second.name != "posts/*" || string(second.data) != "line1\nline2" {
+		t.Fatalf("unexpected second event: %+v, %v", second, err)
+	}
+	if _, err := reader.next(); err == nil {
+		t.Fatalf("expected EOF")
+	}
+}